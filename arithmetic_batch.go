@@ -0,0 +1,35 @@
+package bls12381
+
+// mulBatchGeneric sets c[i] = a[i] * b[i] one element at a time.
+// Slices are expected to have the same length.
+func mulBatchGeneric(c, a, b []fe) {
+	for i := range c {
+		mul(&c[i], &a[i], &b[i])
+	}
+}
+
+// inverseBatch replaces each element with its inverse using Montgomery's
+// trick, so that a single field inversion is spent for the whole slice.
+// Zero elements are left as zero.
+func inverseBatch(e []fe) {
+	if len(e) == 0 {
+		return
+	}
+	prefix := make([]fe, len(e))
+	acc, t := new(fe).set(one()), new(fe)
+	for i := range e {
+		prefix[i].set(acc)
+		if !e[i].isZero() {
+			mul(acc, acc, &e[i])
+		}
+	}
+	inverse(acc, acc)
+	for i := len(e) - 1; i >= 0; i-- {
+		if e[i].isZero() {
+			continue
+		}
+		mul(t, acc, &prefix[i])
+		mul(acc, acc, &e[i])
+		e[i].set(t)
+	}
+}
@@ -8,12 +8,20 @@ import (
 
 var mul func(c, a, b *fe) = mulADX
 var mulAssign func(a, b *fe) = mulAssignADX
+var wmul func(c *wfe, a, b *fe) = wmulADX
+var montRed func(c *fe, a *wfe) = montRedADX
+var mulBatch func(c, a, b []fe) = mulBatchGeneric
 
 func cfgArch() {
 	if !x86ArchitectureSet {
 		if !(cpu.X86.HasADX && cpu.X86.HasBMI2) || forceNonADXArch {
 			mul = mulNoADX
 			mulAssign = mulAssignNoADX
+			wmul = wmulGeneric
+			montRed = montRedGeneric
+		}
+		if cpu.X86.HasAVX512IFMA && !forceNonIFMAArch {
+			mulBatch = mulBatchIFMA
 		}
 		x86ArchitectureSet = true
 	}
@@ -23,6 +31,20 @@ func square(c, a *fe) {
 	mul(c, a, a)
 }
 
+// mulBatchIFMA multiplies eight elements per call with the AVX-512 IFMA
+// kernel and falls back to scalar multiplication for the tail.
+// It panics if a or b is shorter than c since the kernel doesn't check bounds.
+func mulBatchIFMA(c, a, b []fe) {
+	if len(a) < len(c) || len(b) < len(c) {
+		panic("bls12381: mulBatch input is shorter than output")
+	}
+	n := len(c) &^ 7
+	for i := 0; i < n; i += 8 {
+		mulIFMA(&c[i], &a[i], &b[i])
+	}
+	mulBatchGeneric(c[n:], a[n:], b[n:])
+}
+
 func neg(c, a *fe) {
 	if a.isZero() {
 		c.set(a)
@@ -75,3 +97,21 @@ func mulADX(c, a, b *fe)
 
 //go:noescape
 func mulAssignADX(a, b *fe)
+
+//go:noescape
+func wmulADX(c *wfe, a, b *fe)
+
+//go:noescape
+func montRedADX(c *fe, a *wfe)
+
+// mulIFMA multiplies eight consecutive field elements starting at a and b
+// and writes the products to eight consecutive elements starting at c.
+//
+//go:noescape
+func mulIFMA(c, a, b *fe)
+
+//go:noescape
+func wadd(c, a, b *wfe)
+
+//go:noescape
+func wsub(c, a, b *wfe)
@@ -347,6 +347,50 @@ func mul(z, x, y *fe) {
 	}
 }
 
+func wmul(c *wfe, a, b *fe) {
+	wmulGeneric(c, a, b)
+}
+
+func montRed(c *fe, a *wfe) {
+	montRedGeneric(c, a)
+}
+
+func mulBatch(c, a, b []fe) {
+	mulBatchGeneric(c, a, b)
+}
+
+// wadd sets z = x + y mod p * 2^384
+func wadd(z, x, y *wfe) {
+	var carry uint64
+	for i := 0; i < 12; i++ {
+		z[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+	// if z >= p * 2^384 --> z -= p * 2^384
+	var b uint64
+	var s fe
+	for i := 0; i < 6; i++ {
+		s[i], b = bits.Sub64(z[i+6], modulus[i], b)
+	}
+	if b == 0 {
+		copy(z[6:], s[:])
+	}
+}
+
+// wsub sets z = x - y mod p * 2^384
+func wsub(z, x, y *wfe) {
+	var b uint64
+	for i := 0; i < 12; i++ {
+		z[i], b = bits.Sub64(x[i], y[i], b)
+	}
+	// if x < y --> z += p * 2^384
+	if b != 0 {
+		var carry uint64
+		for i := 0; i < 6; i++ {
+			z[i+6], carry = bits.Add64(z[i+6], modulus[i], carry)
+		}
+	}
+}
+
 func mulAssign(z, x *fe) {
 
 	var t [6]uint64
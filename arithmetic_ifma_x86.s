@@ -0,0 +1,576 @@
+// +build amd64,!generic
+
+#include "textflag.h"
+
+// 52-bit limbs of the modulus, -p^-1 mod 2^52, limb masks and the
+// byte offsets of eight consecutive field elements for gathers.
+DATA p52<>+0(SB)/8, $0xeffffffffaaab
+DATA p52<>+8(SB)/8, $0xfeb153ffffb9f
+DATA p52<>+16(SB)/8, $0x6b0f6241eabff
+DATA p52<>+24(SB)/8, $0x12bf6730d2a0f
+DATA p52<>+32(SB)/8, $0x764774b84f385
+DATA p52<>+40(SB)/8, $0x1ba7b6434bacd
+DATA p52<>+48(SB)/8, $0x1ea397fe69a4b
+DATA p52<>+56(SB)/8, $0x000000001a011
+GLOBL p52<>(SB), RODATA, $64
+
+DATA inp52<>+0(SB)/8, $0x3fffcfffcfffd
+GLOBL inp52<>(SB), RODATA, $8
+
+DATA mask52<>+0(SB)/8, $0xfffffffffffff
+GLOBL mask52<>(SB), RODATA, $8
+
+DATA mask20<>+0(SB)/8, $0xfffff
+GLOBL mask20<>(SB), RODATA, $8
+
+DATA lanes<>+0(SB)/8, $0
+DATA lanes<>+8(SB)/8, $48
+DATA lanes<>+16(SB)/8, $96
+DATA lanes<>+24(SB)/8, $144
+DATA lanes<>+32(SB)/8, $192
+DATA lanes<>+40(SB)/8, $240
+DATA lanes<>+48(SB)/8, $288
+DATA lanes<>+56(SB)/8, $336
+GLOBL lanes<>(SB), RODATA, $64
+
+// func mulIFMA(c, a, b *[8]fe)
+TEXT ·mulIFMA(SB), NOSPLIT, $0-24
+	MOVQ a+8(FP), DI
+	MOVQ b+16(FP), SI
+	VMOVDQU64 lanes<>(SB), Z31
+	VPBROADCASTQ mask52<>(SB), Z26
+	// gather and split DI into 52-bit limbs
+	KXNORW K1, K1, K1
+	VPGATHERQQ 0(DI)(Z31*1), K1, Z16
+	KXNORW K1, K1, K1
+	VPGATHERQQ 8(DI)(Z31*1), K1, Z17
+	KXNORW K1, K1, K1
+	VPGATHERQQ 16(DI)(Z31*1), K1, Z18
+	KXNORW K1, K1, K1
+	VPGATHERQQ 24(DI)(Z31*1), K1, Z19
+	KXNORW K1, K1, K1
+	VPGATHERQQ 32(DI)(Z31*1), K1, Z20
+	KXNORW K1, K1, K1
+	VPGATHERQQ 40(DI)(Z31*1), K1, Z21
+	VPANDQ Z26, Z16, Z0
+	VPSRLQ $52, Z16, Z1
+	VPSLLQ $12, Z17, Z27
+	VPORQ Z27, Z1, Z1
+	VPANDQ Z26, Z1, Z1
+	VPSRLQ $40, Z17, Z2
+	VPSLLQ $24, Z18, Z27
+	VPORQ Z27, Z2, Z2
+	VPANDQ Z26, Z2, Z2
+	VPSRLQ $28, Z18, Z3
+	VPSLLQ $36, Z19, Z27
+	VPORQ Z27, Z3, Z3
+	VPANDQ Z26, Z3, Z3
+	VPSRLQ $16, Z19, Z4
+	VPSLLQ $48, Z20, Z27
+	VPORQ Z27, Z4, Z4
+	VPANDQ Z26, Z4, Z4
+	VPSRLQ $4, Z20, Z5
+	VPANDQ Z26, Z5, Z5
+	VPSRLQ $56, Z20, Z6
+	VPSLLQ $8, Z21, Z27
+	VPORQ Z27, Z6, Z6
+	VPANDQ Z26, Z6, Z6
+	VPSRLQ $44, Z21, Z7
+	// gather and split SI into 52-bit limbs
+	KXNORW K1, K1, K1
+	VPGATHERQQ 0(SI)(Z31*1), K1, Z16
+	KXNORW K1, K1, K1
+	VPGATHERQQ 8(SI)(Z31*1), K1, Z17
+	KXNORW K1, K1, K1
+	VPGATHERQQ 16(SI)(Z31*1), K1, Z18
+	KXNORW K1, K1, K1
+	VPGATHERQQ 24(SI)(Z31*1), K1, Z19
+	KXNORW K1, K1, K1
+	VPGATHERQQ 32(SI)(Z31*1), K1, Z20
+	KXNORW K1, K1, K1
+	VPGATHERQQ 40(SI)(Z31*1), K1, Z21
+	VPANDQ Z26, Z16, Z8
+	VPSRLQ $52, Z16, Z9
+	VPSLLQ $12, Z17, Z27
+	VPORQ Z27, Z9, Z9
+	VPANDQ Z26, Z9, Z9
+	VPSRLQ $40, Z17, Z10
+	VPSLLQ $24, Z18, Z27
+	VPORQ Z27, Z10, Z10
+	VPANDQ Z26, Z10, Z10
+	VPSRLQ $28, Z18, Z11
+	VPSLLQ $36, Z19, Z27
+	VPORQ Z27, Z11, Z11
+	VPANDQ Z26, Z11, Z11
+	VPSRLQ $16, Z19, Z12
+	VPSLLQ $48, Z20, Z27
+	VPORQ Z27, Z12, Z12
+	VPANDQ Z26, Z12, Z12
+	VPSRLQ $4, Z20, Z13
+	VPANDQ Z26, Z13, Z13
+	VPSRLQ $56, Z20, Z14
+	VPSLLQ $8, Z21, Z27
+	VPORQ Z27, Z14, Z14
+	VPANDQ Z26, Z14, Z14
+	VPSRLQ $44, Z21, Z15
+
+	// clear accumulator
+	VPXORQ Z16, Z16, Z16
+	VPXORQ Z17, Z17, Z17
+	VPXORQ Z18, Z18, Z18
+	VPXORQ Z19, Z19, Z19
+	VPXORQ Z20, Z20, Z20
+	VPXORQ Z21, Z21, Z21
+	VPXORQ Z22, Z22, Z22
+	VPXORQ Z23, Z23, Z23
+	VPXORQ Z24, Z24, Z24
+
+	// round 0: t += a * b0, t = (t + m * p) / 2^52
+	VPMADD52LUQ Z8, Z0, Z16
+	VPMADD52HUQ Z8, Z0, Z17
+	VPMADD52LUQ Z8, Z1, Z17
+	VPMADD52HUQ Z8, Z1, Z18
+	VPMADD52LUQ Z8, Z2, Z18
+	VPMADD52HUQ Z8, Z2, Z19
+	VPMADD52LUQ Z8, Z3, Z19
+	VPMADD52HUQ Z8, Z3, Z20
+	VPMADD52LUQ Z8, Z4, Z20
+	VPMADD52HUQ Z8, Z4, Z21
+	VPMADD52LUQ Z8, Z5, Z21
+	VPMADD52HUQ Z8, Z5, Z22
+	VPMADD52LUQ Z8, Z6, Z22
+	VPMADD52HUQ Z8, Z6, Z23
+	VPMADD52LUQ Z8, Z7, Z23
+	VPMADD52HUQ Z8, Z7, Z24
+	VPXORQ Z25, Z25, Z25
+	VPMADD52LUQ.BCST inp52<>(SB), Z16, Z25
+	VPMADD52LUQ.BCST p52<>+0(SB), Z25, Z16
+	VPMADD52HUQ.BCST p52<>+0(SB), Z25, Z17
+	VPMADD52LUQ.BCST p52<>+8(SB), Z25, Z17
+	VPMADD52HUQ.BCST p52<>+8(SB), Z25, Z18
+	VPMADD52LUQ.BCST p52<>+16(SB), Z25, Z18
+	VPMADD52HUQ.BCST p52<>+16(SB), Z25, Z19
+	VPMADD52LUQ.BCST p52<>+24(SB), Z25, Z19
+	VPMADD52HUQ.BCST p52<>+24(SB), Z25, Z20
+	VPMADD52LUQ.BCST p52<>+32(SB), Z25, Z20
+	VPMADD52HUQ.BCST p52<>+32(SB), Z25, Z21
+	VPMADD52LUQ.BCST p52<>+40(SB), Z25, Z21
+	VPMADD52HUQ.BCST p52<>+40(SB), Z25, Z22
+	VPMADD52LUQ.BCST p52<>+48(SB), Z25, Z22
+	VPMADD52HUQ.BCST p52<>+48(SB), Z25, Z23
+	VPMADD52LUQ.BCST p52<>+56(SB), Z25, Z23
+	VPMADD52HUQ.BCST p52<>+56(SB), Z25, Z24
+	VPSRLQ $52, Z16, Z27
+	VPADDQ Z27, Z17, Z17
+	VPXORQ Z16, Z16, Z16
+
+	// round 1: t += a * b1, t = (t + m * p) / 2^52
+	VPMADD52LUQ Z9, Z0, Z17
+	VPMADD52HUQ Z9, Z0, Z18
+	VPMADD52LUQ Z9, Z1, Z18
+	VPMADD52HUQ Z9, Z1, Z19
+	VPMADD52LUQ Z9, Z2, Z19
+	VPMADD52HUQ Z9, Z2, Z20
+	VPMADD52LUQ Z9, Z3, Z20
+	VPMADD52HUQ Z9, Z3, Z21
+	VPMADD52LUQ Z9, Z4, Z21
+	VPMADD52HUQ Z9, Z4, Z22
+	VPMADD52LUQ Z9, Z5, Z22
+	VPMADD52HUQ Z9, Z5, Z23
+	VPMADD52LUQ Z9, Z6, Z23
+	VPMADD52HUQ Z9, Z6, Z24
+	VPMADD52LUQ Z9, Z7, Z24
+	VPMADD52HUQ Z9, Z7, Z16
+	VPXORQ Z25, Z25, Z25
+	VPMADD52LUQ.BCST inp52<>(SB), Z17, Z25
+	VPMADD52LUQ.BCST p52<>+0(SB), Z25, Z17
+	VPMADD52HUQ.BCST p52<>+0(SB), Z25, Z18
+	VPMADD52LUQ.BCST p52<>+8(SB), Z25, Z18
+	VPMADD52HUQ.BCST p52<>+8(SB), Z25, Z19
+	VPMADD52LUQ.BCST p52<>+16(SB), Z25, Z19
+	VPMADD52HUQ.BCST p52<>+16(SB), Z25, Z20
+	VPMADD52LUQ.BCST p52<>+24(SB), Z25, Z20
+	VPMADD52HUQ.BCST p52<>+24(SB), Z25, Z21
+	VPMADD52LUQ.BCST p52<>+32(SB), Z25, Z21
+	VPMADD52HUQ.BCST p52<>+32(SB), Z25, Z22
+	VPMADD52LUQ.BCST p52<>+40(SB), Z25, Z22
+	VPMADD52HUQ.BCST p52<>+40(SB), Z25, Z23
+	VPMADD52LUQ.BCST p52<>+48(SB), Z25, Z23
+	VPMADD52HUQ.BCST p52<>+48(SB), Z25, Z24
+	VPMADD52LUQ.BCST p52<>+56(SB), Z25, Z24
+	VPMADD52HUQ.BCST p52<>+56(SB), Z25, Z16
+	VPSRLQ $52, Z17, Z27
+	VPADDQ Z27, Z18, Z18
+	VPXORQ Z17, Z17, Z17
+
+	// round 2: t += a * b2, t = (t + m * p) / 2^52
+	VPMADD52LUQ Z10, Z0, Z18
+	VPMADD52HUQ Z10, Z0, Z19
+	VPMADD52LUQ Z10, Z1, Z19
+	VPMADD52HUQ Z10, Z1, Z20
+	VPMADD52LUQ Z10, Z2, Z20
+	VPMADD52HUQ Z10, Z2, Z21
+	VPMADD52LUQ Z10, Z3, Z21
+	VPMADD52HUQ Z10, Z3, Z22
+	VPMADD52LUQ Z10, Z4, Z22
+	VPMADD52HUQ Z10, Z4, Z23
+	VPMADD52LUQ Z10, Z5, Z23
+	VPMADD52HUQ Z10, Z5, Z24
+	VPMADD52LUQ Z10, Z6, Z24
+	VPMADD52HUQ Z10, Z6, Z16
+	VPMADD52LUQ Z10, Z7, Z16
+	VPMADD52HUQ Z10, Z7, Z17
+	VPXORQ Z25, Z25, Z25
+	VPMADD52LUQ.BCST inp52<>(SB), Z18, Z25
+	VPMADD52LUQ.BCST p52<>+0(SB), Z25, Z18
+	VPMADD52HUQ.BCST p52<>+0(SB), Z25, Z19
+	VPMADD52LUQ.BCST p52<>+8(SB), Z25, Z19
+	VPMADD52HUQ.BCST p52<>+8(SB), Z25, Z20
+	VPMADD52LUQ.BCST p52<>+16(SB), Z25, Z20
+	VPMADD52HUQ.BCST p52<>+16(SB), Z25, Z21
+	VPMADD52LUQ.BCST p52<>+24(SB), Z25, Z21
+	VPMADD52HUQ.BCST p52<>+24(SB), Z25, Z22
+	VPMADD52LUQ.BCST p52<>+32(SB), Z25, Z22
+	VPMADD52HUQ.BCST p52<>+32(SB), Z25, Z23
+	VPMADD52LUQ.BCST p52<>+40(SB), Z25, Z23
+	VPMADD52HUQ.BCST p52<>+40(SB), Z25, Z24
+	VPMADD52LUQ.BCST p52<>+48(SB), Z25, Z24
+	VPMADD52HUQ.BCST p52<>+48(SB), Z25, Z16
+	VPMADD52LUQ.BCST p52<>+56(SB), Z25, Z16
+	VPMADD52HUQ.BCST p52<>+56(SB), Z25, Z17
+	VPSRLQ $52, Z18, Z27
+	VPADDQ Z27, Z19, Z19
+	VPXORQ Z18, Z18, Z18
+
+	// round 3: t += a * b3, t = (t + m * p) / 2^52
+	VPMADD52LUQ Z11, Z0, Z19
+	VPMADD52HUQ Z11, Z0, Z20
+	VPMADD52LUQ Z11, Z1, Z20
+	VPMADD52HUQ Z11, Z1, Z21
+	VPMADD52LUQ Z11, Z2, Z21
+	VPMADD52HUQ Z11, Z2, Z22
+	VPMADD52LUQ Z11, Z3, Z22
+	VPMADD52HUQ Z11, Z3, Z23
+	VPMADD52LUQ Z11, Z4, Z23
+	VPMADD52HUQ Z11, Z4, Z24
+	VPMADD52LUQ Z11, Z5, Z24
+	VPMADD52HUQ Z11, Z5, Z16
+	VPMADD52LUQ Z11, Z6, Z16
+	VPMADD52HUQ Z11, Z6, Z17
+	VPMADD52LUQ Z11, Z7, Z17
+	VPMADD52HUQ Z11, Z7, Z18
+	VPXORQ Z25, Z25, Z25
+	VPMADD52LUQ.BCST inp52<>(SB), Z19, Z25
+	VPMADD52LUQ.BCST p52<>+0(SB), Z25, Z19
+	VPMADD52HUQ.BCST p52<>+0(SB), Z25, Z20
+	VPMADD52LUQ.BCST p52<>+8(SB), Z25, Z20
+	VPMADD52HUQ.BCST p52<>+8(SB), Z25, Z21
+	VPMADD52LUQ.BCST p52<>+16(SB), Z25, Z21
+	VPMADD52HUQ.BCST p52<>+16(SB), Z25, Z22
+	VPMADD52LUQ.BCST p52<>+24(SB), Z25, Z22
+	VPMADD52HUQ.BCST p52<>+24(SB), Z25, Z23
+	VPMADD52LUQ.BCST p52<>+32(SB), Z25, Z23
+	VPMADD52HUQ.BCST p52<>+32(SB), Z25, Z24
+	VPMADD52LUQ.BCST p52<>+40(SB), Z25, Z24
+	VPMADD52HUQ.BCST p52<>+40(SB), Z25, Z16
+	VPMADD52LUQ.BCST p52<>+48(SB), Z25, Z16
+	VPMADD52HUQ.BCST p52<>+48(SB), Z25, Z17
+	VPMADD52LUQ.BCST p52<>+56(SB), Z25, Z17
+	VPMADD52HUQ.BCST p52<>+56(SB), Z25, Z18
+	VPSRLQ $52, Z19, Z27
+	VPADDQ Z27, Z20, Z20
+	VPXORQ Z19, Z19, Z19
+
+	// round 4: t += a * b4, t = (t + m * p) / 2^52
+	VPMADD52LUQ Z12, Z0, Z20
+	VPMADD52HUQ Z12, Z0, Z21
+	VPMADD52LUQ Z12, Z1, Z21
+	VPMADD52HUQ Z12, Z1, Z22
+	VPMADD52LUQ Z12, Z2, Z22
+	VPMADD52HUQ Z12, Z2, Z23
+	VPMADD52LUQ Z12, Z3, Z23
+	VPMADD52HUQ Z12, Z3, Z24
+	VPMADD52LUQ Z12, Z4, Z24
+	VPMADD52HUQ Z12, Z4, Z16
+	VPMADD52LUQ Z12, Z5, Z16
+	VPMADD52HUQ Z12, Z5, Z17
+	VPMADD52LUQ Z12, Z6, Z17
+	VPMADD52HUQ Z12, Z6, Z18
+	VPMADD52LUQ Z12, Z7, Z18
+	VPMADD52HUQ Z12, Z7, Z19
+	VPXORQ Z25, Z25, Z25
+	VPMADD52LUQ.BCST inp52<>(SB), Z20, Z25
+	VPMADD52LUQ.BCST p52<>+0(SB), Z25, Z20
+	VPMADD52HUQ.BCST p52<>+0(SB), Z25, Z21
+	VPMADD52LUQ.BCST p52<>+8(SB), Z25, Z21
+	VPMADD52HUQ.BCST p52<>+8(SB), Z25, Z22
+	VPMADD52LUQ.BCST p52<>+16(SB), Z25, Z22
+	VPMADD52HUQ.BCST p52<>+16(SB), Z25, Z23
+	VPMADD52LUQ.BCST p52<>+24(SB), Z25, Z23
+	VPMADD52HUQ.BCST p52<>+24(SB), Z25, Z24
+	VPMADD52LUQ.BCST p52<>+32(SB), Z25, Z24
+	VPMADD52HUQ.BCST p52<>+32(SB), Z25, Z16
+	VPMADD52LUQ.BCST p52<>+40(SB), Z25, Z16
+	VPMADD52HUQ.BCST p52<>+40(SB), Z25, Z17
+	VPMADD52LUQ.BCST p52<>+48(SB), Z25, Z17
+	VPMADD52HUQ.BCST p52<>+48(SB), Z25, Z18
+	VPMADD52LUQ.BCST p52<>+56(SB), Z25, Z18
+	VPMADD52HUQ.BCST p52<>+56(SB), Z25, Z19
+	VPSRLQ $52, Z20, Z27
+	VPADDQ Z27, Z21, Z21
+	VPXORQ Z20, Z20, Z20
+
+	// round 5: t += a * b5, t = (t + m * p) / 2^52
+	VPMADD52LUQ Z13, Z0, Z21
+	VPMADD52HUQ Z13, Z0, Z22
+	VPMADD52LUQ Z13, Z1, Z22
+	VPMADD52HUQ Z13, Z1, Z23
+	VPMADD52LUQ Z13, Z2, Z23
+	VPMADD52HUQ Z13, Z2, Z24
+	VPMADD52LUQ Z13, Z3, Z24
+	VPMADD52HUQ Z13, Z3, Z16
+	VPMADD52LUQ Z13, Z4, Z16
+	VPMADD52HUQ Z13, Z4, Z17
+	VPMADD52LUQ Z13, Z5, Z17
+	VPMADD52HUQ Z13, Z5, Z18
+	VPMADD52LUQ Z13, Z6, Z18
+	VPMADD52HUQ Z13, Z6, Z19
+	VPMADD52LUQ Z13, Z7, Z19
+	VPMADD52HUQ Z13, Z7, Z20
+	VPXORQ Z25, Z25, Z25
+	VPMADD52LUQ.BCST inp52<>(SB), Z21, Z25
+	VPMADD52LUQ.BCST p52<>+0(SB), Z25, Z21
+	VPMADD52HUQ.BCST p52<>+0(SB), Z25, Z22
+	VPMADD52LUQ.BCST p52<>+8(SB), Z25, Z22
+	VPMADD52HUQ.BCST p52<>+8(SB), Z25, Z23
+	VPMADD52LUQ.BCST p52<>+16(SB), Z25, Z23
+	VPMADD52HUQ.BCST p52<>+16(SB), Z25, Z24
+	VPMADD52LUQ.BCST p52<>+24(SB), Z25, Z24
+	VPMADD52HUQ.BCST p52<>+24(SB), Z25, Z16
+	VPMADD52LUQ.BCST p52<>+32(SB), Z25, Z16
+	VPMADD52HUQ.BCST p52<>+32(SB), Z25, Z17
+	VPMADD52LUQ.BCST p52<>+40(SB), Z25, Z17
+	VPMADD52HUQ.BCST p52<>+40(SB), Z25, Z18
+	VPMADD52LUQ.BCST p52<>+48(SB), Z25, Z18
+	VPMADD52HUQ.BCST p52<>+48(SB), Z25, Z19
+	VPMADD52LUQ.BCST p52<>+56(SB), Z25, Z19
+	VPMADD52HUQ.BCST p52<>+56(SB), Z25, Z20
+	VPSRLQ $52, Z21, Z27
+	VPADDQ Z27, Z22, Z22
+	VPXORQ Z21, Z21, Z21
+
+	// round 6: t += a * b6, t = (t + m * p) / 2^52
+	VPMADD52LUQ Z14, Z0, Z22
+	VPMADD52HUQ Z14, Z0, Z23
+	VPMADD52LUQ Z14, Z1, Z23
+	VPMADD52HUQ Z14, Z1, Z24
+	VPMADD52LUQ Z14, Z2, Z24
+	VPMADD52HUQ Z14, Z2, Z16
+	VPMADD52LUQ Z14, Z3, Z16
+	VPMADD52HUQ Z14, Z3, Z17
+	VPMADD52LUQ Z14, Z4, Z17
+	VPMADD52HUQ Z14, Z4, Z18
+	VPMADD52LUQ Z14, Z5, Z18
+	VPMADD52HUQ Z14, Z5, Z19
+	VPMADD52LUQ Z14, Z6, Z19
+	VPMADD52HUQ Z14, Z6, Z20
+	VPMADD52LUQ Z14, Z7, Z20
+	VPMADD52HUQ Z14, Z7, Z21
+	VPXORQ Z25, Z25, Z25
+	VPMADD52LUQ.BCST inp52<>(SB), Z22, Z25
+	VPMADD52LUQ.BCST p52<>+0(SB), Z25, Z22
+	VPMADD52HUQ.BCST p52<>+0(SB), Z25, Z23
+	VPMADD52LUQ.BCST p52<>+8(SB), Z25, Z23
+	VPMADD52HUQ.BCST p52<>+8(SB), Z25, Z24
+	VPMADD52LUQ.BCST p52<>+16(SB), Z25, Z24
+	VPMADD52HUQ.BCST p52<>+16(SB), Z25, Z16
+	VPMADD52LUQ.BCST p52<>+24(SB), Z25, Z16
+	VPMADD52HUQ.BCST p52<>+24(SB), Z25, Z17
+	VPMADD52LUQ.BCST p52<>+32(SB), Z25, Z17
+	VPMADD52HUQ.BCST p52<>+32(SB), Z25, Z18
+	VPMADD52LUQ.BCST p52<>+40(SB), Z25, Z18
+	VPMADD52HUQ.BCST p52<>+40(SB), Z25, Z19
+	VPMADD52LUQ.BCST p52<>+48(SB), Z25, Z19
+	VPMADD52HUQ.BCST p52<>+48(SB), Z25, Z20
+	VPMADD52LUQ.BCST p52<>+56(SB), Z25, Z20
+	VPMADD52HUQ.BCST p52<>+56(SB), Z25, Z21
+	VPSRLQ $52, Z22, Z27
+	VPADDQ Z27, Z23, Z23
+	VPXORQ Z22, Z22, Z22
+
+	// round 7: t += a * b7, t = (t + m * p) / 2^20
+	VPMADD52LUQ Z15, Z0, Z23
+	VPMADD52HUQ Z15, Z0, Z24
+	VPMADD52LUQ Z15, Z1, Z24
+	VPMADD52HUQ Z15, Z1, Z16
+	VPMADD52LUQ Z15, Z2, Z16
+	VPMADD52HUQ Z15, Z2, Z17
+	VPMADD52LUQ Z15, Z3, Z17
+	VPMADD52HUQ Z15, Z3, Z18
+	VPMADD52LUQ Z15, Z4, Z18
+	VPMADD52HUQ Z15, Z4, Z19
+	VPMADD52LUQ Z15, Z5, Z19
+	VPMADD52HUQ Z15, Z5, Z20
+	VPMADD52LUQ Z15, Z6, Z20
+	VPMADD52HUQ Z15, Z6, Z21
+	VPMADD52LUQ Z15, Z7, Z21
+	VPMADD52HUQ Z15, Z7, Z22
+	VPXORQ Z25, Z25, Z25
+	VPMADD52LUQ.BCST inp52<>(SB), Z23, Z25
+	VPANDQ.BCST mask20<>(SB), Z25, Z25
+	VPMADD52LUQ.BCST p52<>+0(SB), Z25, Z23
+	VPMADD52HUQ.BCST p52<>+0(SB), Z25, Z24
+	VPMADD52LUQ.BCST p52<>+8(SB), Z25, Z24
+	VPMADD52HUQ.BCST p52<>+8(SB), Z25, Z16
+	VPMADD52LUQ.BCST p52<>+16(SB), Z25, Z16
+	VPMADD52HUQ.BCST p52<>+16(SB), Z25, Z17
+	VPMADD52LUQ.BCST p52<>+24(SB), Z25, Z17
+	VPMADD52HUQ.BCST p52<>+24(SB), Z25, Z18
+	VPMADD52LUQ.BCST p52<>+32(SB), Z25, Z18
+	VPMADD52HUQ.BCST p52<>+32(SB), Z25, Z19
+	VPMADD52LUQ.BCST p52<>+40(SB), Z25, Z19
+	VPMADD52HUQ.BCST p52<>+40(SB), Z25, Z20
+	VPMADD52LUQ.BCST p52<>+48(SB), Z25, Z20
+	VPMADD52HUQ.BCST p52<>+48(SB), Z25, Z21
+	VPMADD52LUQ.BCST p52<>+56(SB), Z25, Z21
+	VPMADD52HUQ.BCST p52<>+56(SB), Z25, Z22
+
+	// normalize limbs
+	VPSRLQ $52, Z23, Z27
+	VPADDQ Z27, Z24, Z24
+	VPANDQ Z26, Z23, Z23
+	VPSRLQ $52, Z24, Z27
+	VPADDQ Z27, Z16, Z16
+	VPANDQ Z26, Z24, Z24
+	VPSRLQ $52, Z16, Z27
+	VPADDQ Z27, Z17, Z17
+	VPANDQ Z26, Z16, Z16
+	VPSRLQ $52, Z17, Z27
+	VPADDQ Z27, Z18, Z18
+	VPANDQ Z26, Z17, Z17
+	VPSRLQ $52, Z18, Z27
+	VPADDQ Z27, Z19, Z19
+	VPANDQ Z26, Z18, Z18
+	VPSRLQ $52, Z19, Z27
+	VPADDQ Z27, Z20, Z20
+	VPANDQ Z26, Z19, Z19
+	VPSRLQ $52, Z20, Z27
+	VPADDQ Z27, Z21, Z21
+	VPANDQ Z26, Z20, Z20
+	VPSRLQ $52, Z21, Z27
+	VPADDQ Z27, Z22, Z22
+	VPANDQ Z26, Z21, Z21
+
+	// shift out the remaining 20 bits
+	VPSRLQ $20, Z23, Z0
+	VPSLLQ $32, Z24, Z27
+	VPANDQ Z26, Z27, Z27
+	VPORQ Z27, Z0, Z0
+	VPSRLQ $20, Z24, Z1
+	VPSLLQ $32, Z16, Z27
+	VPANDQ Z26, Z27, Z27
+	VPORQ Z27, Z1, Z1
+	VPSRLQ $20, Z16, Z2
+	VPSLLQ $32, Z17, Z27
+	VPANDQ Z26, Z27, Z27
+	VPORQ Z27, Z2, Z2
+	VPSRLQ $20, Z17, Z3
+	VPSLLQ $32, Z18, Z27
+	VPANDQ Z26, Z27, Z27
+	VPORQ Z27, Z3, Z3
+	VPSRLQ $20, Z18, Z4
+	VPSLLQ $32, Z19, Z27
+	VPANDQ Z26, Z27, Z27
+	VPORQ Z27, Z4, Z4
+	VPSRLQ $20, Z19, Z5
+	VPSLLQ $32, Z20, Z27
+	VPANDQ Z26, Z27, Z27
+	VPORQ Z27, Z5, Z5
+	VPSRLQ $20, Z20, Z6
+	VPSLLQ $32, Z21, Z27
+	VPANDQ Z26, Z27, Z27
+	VPORQ Z27, Z6, Z6
+	VPSRLQ $20, Z21, Z7
+
+	// d = r - p
+	VPXORQ Z28, Z28, Z28
+	VPSUBQ.BCST p52<>+0(SB), Z0, Z8
+	VPADDQ Z28, Z8, Z8
+	VPSRAQ $52, Z8, Z28
+	VPANDQ Z26, Z8, Z8
+	VPSUBQ.BCST p52<>+8(SB), Z1, Z9
+	VPADDQ Z28, Z9, Z9
+	VPSRAQ $52, Z9, Z28
+	VPANDQ Z26, Z9, Z9
+	VPSUBQ.BCST p52<>+16(SB), Z2, Z10
+	VPADDQ Z28, Z10, Z10
+	VPSRAQ $52, Z10, Z28
+	VPANDQ Z26, Z10, Z10
+	VPSUBQ.BCST p52<>+24(SB), Z3, Z11
+	VPADDQ Z28, Z11, Z11
+	VPSRAQ $52, Z11, Z28
+	VPANDQ Z26, Z11, Z11
+	VPSUBQ.BCST p52<>+32(SB), Z4, Z12
+	VPADDQ Z28, Z12, Z12
+	VPSRAQ $52, Z12, Z28
+	VPANDQ Z26, Z12, Z12
+	VPSUBQ.BCST p52<>+40(SB), Z5, Z13
+	VPADDQ Z28, Z13, Z13
+	VPSRAQ $52, Z13, Z28
+	VPANDQ Z26, Z13, Z13
+	VPSUBQ.BCST p52<>+48(SB), Z6, Z14
+	VPADDQ Z28, Z14, Z14
+	VPSRAQ $52, Z14, Z28
+	VPANDQ Z26, Z14, Z14
+	VPSUBQ.BCST p52<>+56(SB), Z7, Z15
+	VPADDQ Z28, Z15, Z15
+	VPSRAQ $52, Z15, Z28
+	VPANDQ Z26, Z15, Z15
+
+	// keep r on borrow, d otherwise
+	VPTESTMQ Z28, Z28, K2
+	VPBLENDMQ Z0, Z8, K2, Z0
+	VPBLENDMQ Z1, Z9, K2, Z1
+	VPBLENDMQ Z2, Z10, K2, Z2
+	VPBLENDMQ Z3, Z11, K2, Z3
+	VPBLENDMQ Z4, Z12, K2, Z4
+	VPBLENDMQ Z5, Z13, K2, Z5
+	VPBLENDMQ Z6, Z14, K2, Z6
+	VPBLENDMQ Z7, Z15, K2, Z7
+
+	// join limbs and scatter
+	VMOVDQA64 Z0, Z16
+	VPSLLQ $52, Z1, Z27
+	VPORQ Z27, Z16, Z16
+	VPSRLQ $12, Z1, Z17
+	VPSLLQ $40, Z2, Z27
+	VPORQ Z27, Z17, Z17
+	VPSRLQ $24, Z2, Z18
+	VPSLLQ $28, Z3, Z27
+	VPORQ Z27, Z18, Z18
+	VPSRLQ $36, Z3, Z19
+	VPSLLQ $16, Z4, Z27
+	VPORQ Z27, Z19, Z19
+	VPSRLQ $48, Z4, Z20
+	VPSLLQ $4, Z5, Z27
+	VPORQ Z27, Z20, Z20
+	VPSLLQ $56, Z6, Z27
+	VPORQ Z27, Z20, Z20
+	VPSRLQ $8, Z6, Z21
+	VPSLLQ $44, Z7, Z27
+	VPORQ Z27, Z21, Z21
+	MOVQ c+0(FP), DI
+	KXNORW K1, K1, K1
+	VPSCATTERQQ Z16, K1, 0(DI)(Z31*1)
+	KXNORW K1, K1, K1
+	VPSCATTERQQ Z17, K1, 8(DI)(Z31*1)
+	KXNORW K1, K1, K1
+	VPSCATTERQQ Z18, K1, 16(DI)(Z31*1)
+	KXNORW K1, K1, K1
+	VPSCATTERQQ Z19, K1, 24(DI)(Z31*1)
+	KXNORW K1, K1, K1
+	VPSCATTERQQ Z20, K1, 32(DI)(Z31*1)
+	KXNORW K1, K1, K1
+	VPSCATTERQQ Z21, K1, 40(DI)(Z31*1)
+	VZEROUPPER
+	RET
@@ -0,0 +1,64 @@
+package bls12381
+
+import "math/bits"
+
+// Double-width (768-bit) arithmetic for lazy reduction in extension fields.
+// Wide values are kept in [0, p * 2^384) so that montRed of any of them,
+// or of their sums and differences via wadd and wsub, is a valid Montgomery reduction.
+
+// wmulGeneric sets c = a * b without reduction.
+// Inputs are expected to be less than 2^384.
+func wmulGeneric(c *wfe, a, b *fe) {
+	var w wfe
+	for i := 0; i < 6; i++ {
+		var carry uint64
+		for j := 0; j < 6; j++ {
+			hi, lo := bits.Mul64(a[j], b[i])
+			var k uint64
+			lo, k = bits.Add64(lo, w[i+j], 0)
+			hi += k
+			lo, k = bits.Add64(lo, carry, 0)
+			hi += k
+			w[i+j] = lo
+			carry = hi
+		}
+		w[i+6] = carry
+	}
+	*c = w
+}
+
+// montRedGeneric sets c = w * 2^-384 mod p.
+// Input is expected to be less than p * 2^384.
+func montRedGeneric(c *fe, a *wfe) {
+	w := *a
+	var hi uint64
+	for i := 0; i < 6; i++ {
+		m := w[i] * inp
+		var carry uint64
+		for j := 0; j < 6; j++ {
+			h, l := bits.Mul64(m, modulus[j])
+			var k uint64
+			l, k = bits.Add64(l, w[i+j], 0)
+			h += k
+			l, k = bits.Add64(l, carry, 0)
+			h += k
+			w[i+j] = l
+			carry = h
+		}
+		w[i+6], hi = bits.Add64(w[i+6], carry, hi)
+	}
+	// result is less than 2p
+	var b uint64
+	var s fe
+	s[0], b = bits.Sub64(w[6], modulus[0], 0)
+	s[1], b = bits.Sub64(w[7], modulus[1], b)
+	s[2], b = bits.Sub64(w[8], modulus[2], b)
+	s[3], b = bits.Sub64(w[9], modulus[3], b)
+	s[4], b = bits.Sub64(w[10], modulus[4], b)
+	s[5], b = bits.Sub64(w[11], modulus[5], b)
+	// keep w if subtraction borrows, selected with a mask rather than a branch
+	mask := -b
+	for i := 0; i < 6; i++ {
+		c[i] = s[i] ^ (mask & (s[i] ^ w[i+6]))
+	}
+}
@@ -3149,4 +3149,641 @@ TEXT ·mulAssignADX(SB), NOSPLIT, $16-16
 	MOVQ R10, 40(SI)
 	RET
 
-/*	 | end								 		*/
\ No newline at end of file
+/*	 | end								 		*/
+
+// double-precision multiplication w/o reduction
+// c = a * b
+TEXT ·wmulADX(SB), NOSPLIT, $16-24
+
+/*	 | inputs							 			*/
+
+	MOVQ a+8(FP), DI
+	MOVQ b+16(FP), SI
+
+/*	 | multiplication phase 		*/
+
+	// | w = a * b
+	// | a = (a0, a1, a2, a3, a4, a5)
+	// | b = (b0, b1, b2, b3, b4, b5)
+	// | w = (w0, w1, w2, w3, w4, w5, w6, w7, w8, w9, w10, w11)
+
+/*	 | i = 0									 	*/
+
+	MOVQ (SI), DX
+	XORQ AX, AX
+
+	MULXQ (DI), AX, R8
+	MOVQ AX, CX
+
+	MULXQ 8(DI), AX, R9
+	ADCXQ AX, R8
+
+	MULXQ 16(DI), AX, R10
+	ADCXQ AX, R9
+
+	MULXQ 24(DI), AX, R11
+	ADCXQ AX, R10
+
+	MULXQ 32(DI), AX, R12
+	ADCXQ AX, R11
+
+	MULXQ 40(DI), AX, R13
+	ADCXQ AX, R12
+	ADCQ $0, R13
+
+/*	 | i = 1									 	*/
+
+	MOVQ 8(SI), DX
+	XORQ R14, R14
+
+	MULXQ (DI), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+	MOVQ R8, (SP)
+
+	MULXQ 8(DI), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+
+	MULXQ 16(DI), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+
+	MULXQ 24(DI), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+
+	MULXQ 32(DI), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+
+	MULXQ 40(DI), AX, BX
+	ADOXQ AX, R13
+	ADOXQ R14, R14
+	ADCXQ BX, R14
+
+/*	 | i = 2									 	*/
+
+	MOVQ 16(SI), DX
+	XORQ R15, R15
+
+	MULXQ (DI), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+	MOVQ R9, 8(SP)
+
+	MULXQ 8(DI), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+
+	MULXQ 16(DI), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+
+	MULXQ 24(DI), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+
+	MULXQ 32(DI), AX, BX
+	ADOXQ AX, R13
+	ADCXQ BX, R14
+
+	MULXQ 40(DI), AX, BX
+	ADOXQ AX, R14
+	ADOXQ R15, R15
+	ADCXQ BX, R15
+
+/*	 | i = 3									 	*/
+
+	MOVQ 24(SI), DX
+	XORQ R8, R8
+
+	MULXQ (DI), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+
+	MULXQ 8(DI), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+
+	MULXQ 16(DI), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+
+	MULXQ 24(DI), AX, BX
+	ADOXQ AX, R13
+	ADCXQ BX, R14
+
+	MULXQ 32(DI), AX, BX
+	ADOXQ AX, R14
+	ADCXQ BX, R15
+
+	MULXQ 40(DI), AX, BX
+	ADOXQ AX, R15
+	ADOXQ R8, R8
+	ADCXQ BX, R8
+
+/*	 | i = 4									 	*/
+
+	MOVQ 32(SI), DX
+	XORQ R9, R9
+
+	MULXQ (DI), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+
+	MULXQ 8(DI), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+
+	MULXQ 16(DI), AX, BX
+	ADOXQ AX, R13
+	ADCXQ BX, R14
+
+	MULXQ 24(DI), AX, BX
+	ADOXQ AX, R14
+	ADCXQ BX, R15
+
+	MULXQ 32(DI), AX, BX
+	ADOXQ AX, R15
+	ADCXQ BX, R8
+
+	MULXQ 40(DI), AX, BX
+	ADOXQ AX, R8
+	ADOXQ R9, R9
+	ADCXQ BX, R9
+
+/*	 | i = 5									 	*/
+
+	MOVQ 40(SI), DX
+	XORQ SI, SI
+
+	MULXQ (DI), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+
+	MULXQ 8(DI), AX, BX
+	ADOXQ AX, R13
+	ADCXQ BX, R14
+
+	MULXQ 16(DI), AX, BX
+	ADOXQ AX, R14
+	ADCXQ BX, R15
+
+	MULXQ 24(DI), AX, BX
+	ADOXQ AX, R15
+	ADCXQ BX, R8
+
+	MULXQ 32(DI), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+
+	MULXQ 40(DI), AX, BX
+	ADOXQ AX, R9
+	ADOXQ BX, SI
+
+	// |  w0,  w1,  w2,   w3,  w4,  w5,
+	// | 	CX,   0,   8,  R10, R11, R12,
+	// |  w6,  w7,  w8,  w9,  w10, w11,
+
+/*	 | out								 			*/
+
+	MOVQ c+0(FP), DI
+	MOVQ CX, (DI)
+	MOVQ (SP), AX
+	MOVQ AX, 8(DI)
+	MOVQ 8(SP), AX
+	MOVQ AX, 16(DI)
+	MOVQ R10, 24(DI)
+	MOVQ R11, 32(DI)
+	MOVQ R12, 40(DI)
+	MOVQ R13, 48(DI)
+	MOVQ R14, 56(DI)
+	MOVQ R15, 64(DI)
+	MOVQ R8, 72(DI)
+	MOVQ R9, 80(DI)
+	MOVQ SI, 88(DI)
+	RET
+
+/*	 | end											*/
+
+
+// montgomery reduction
+// c = a * R^-1 % p, a < p * R
+TEXT ·montRedADX(SB), NOSPLIT, $0-16
+
+/*	 | inputs							 			*/
+
+	MOVQ a+8(FP), SI
+	MOVQ (SI), CX
+	MOVQ 8(SI), R8
+	MOVQ 16(SI), R9
+	MOVQ 24(SI), R10
+	MOVQ 32(SI), R11
+	MOVQ 40(SI), R12
+	MOVQ 48(SI), R13
+	MOVQ $0, DI
+
+/*	 | i = 0										*/
+
+	MOVQ CX, DX
+	MULXQ ·inp+0(SB), DX, AX
+	XORQ AX, AX
+
+	MULXQ ·modulus+0(SB), AX, BX
+	ADOXQ AX, CX
+	ADCXQ BX, R8
+
+	MULXQ ·modulus+8(SB), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+
+	MULXQ ·modulus+16(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+
+	MULXQ ·modulus+24(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+
+	MULXQ ·modulus+32(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+
+	MULXQ ·modulus+40(SB), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+
+	// | carry from previous round and both flags into the next word
+	MOVQ $0, AX
+	ADOXQ DI, R13
+	MOVQ $0, DI
+	ADCXQ AX, DI
+	ADOXQ AX, DI
+
+/*	 | i = 1										*/
+
+	MOVQ 56(SI), CX
+	MOVQ R8, DX
+	MULXQ ·inp+0(SB), DX, AX
+	XORQ AX, AX
+
+	MULXQ ·modulus+0(SB), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+
+	MULXQ ·modulus+8(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+
+	MULXQ ·modulus+16(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+
+	MULXQ ·modulus+24(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+
+	MULXQ ·modulus+32(SB), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+
+	MULXQ ·modulus+40(SB), AX, BX
+	ADOXQ AX, R13
+	ADCXQ BX, CX
+
+	// | carry from previous round and both flags into the next word
+	MOVQ $0, AX
+	ADOXQ DI, CX
+	MOVQ $0, DI
+	ADCXQ AX, DI
+	ADOXQ AX, DI
+
+/*	 | i = 2										*/
+
+	MOVQ 64(SI), R8
+	MOVQ R9, DX
+	MULXQ ·inp+0(SB), DX, AX
+	XORQ AX, AX
+
+	MULXQ ·modulus+0(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+
+	MULXQ ·modulus+8(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+
+	MULXQ ·modulus+16(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+
+	MULXQ ·modulus+24(SB), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+
+	MULXQ ·modulus+32(SB), AX, BX
+	ADOXQ AX, R13
+	ADCXQ BX, CX
+
+	MULXQ ·modulus+40(SB), AX, BX
+	ADOXQ AX, CX
+	ADCXQ BX, R8
+
+	// | carry from previous round and both flags into the next word
+	MOVQ $0, AX
+	ADOXQ DI, R8
+	MOVQ $0, DI
+	ADCXQ AX, DI
+	ADOXQ AX, DI
+
+/*	 | i = 3										*/
+
+	MOVQ 72(SI), R9
+	MOVQ R10, DX
+	MULXQ ·inp+0(SB), DX, AX
+	XORQ AX, AX
+
+	MULXQ ·modulus+0(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+
+	MULXQ ·modulus+8(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+
+	MULXQ ·modulus+16(SB), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+
+	MULXQ ·modulus+24(SB), AX, BX
+	ADOXQ AX, R13
+	ADCXQ BX, CX
+
+	MULXQ ·modulus+32(SB), AX, BX
+	ADOXQ AX, CX
+	ADCXQ BX, R8
+
+	MULXQ ·modulus+40(SB), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+
+	// | carry from previous round and both flags into the next word
+	MOVQ $0, AX
+	ADOXQ DI, R9
+	MOVQ $0, DI
+	ADCXQ AX, DI
+	ADOXQ AX, DI
+
+/*	 | i = 4										*/
+
+	MOVQ 80(SI), R10
+	MOVQ R11, DX
+	MULXQ ·inp+0(SB), DX, AX
+	XORQ AX, AX
+
+	MULXQ ·modulus+0(SB), AX, BX
+	ADOXQ AX, R11
+	ADCXQ BX, R12
+
+	MULXQ ·modulus+8(SB), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+
+	MULXQ ·modulus+16(SB), AX, BX
+	ADOXQ AX, R13
+	ADCXQ BX, CX
+
+	MULXQ ·modulus+24(SB), AX, BX
+	ADOXQ AX, CX
+	ADCXQ BX, R8
+
+	MULXQ ·modulus+32(SB), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+
+	MULXQ ·modulus+40(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+
+	// | carry from previous round and both flags into the next word
+	MOVQ $0, AX
+	ADOXQ DI, R10
+	MOVQ $0, DI
+	ADCXQ AX, DI
+	ADOXQ AX, DI
+
+/*	 | i = 5										*/
+
+	MOVQ 88(SI), R11
+	MOVQ R12, DX
+	MULXQ ·inp+0(SB), DX, AX
+	XORQ AX, AX
+
+	MULXQ ·modulus+0(SB), AX, BX
+	ADOXQ AX, R12
+	ADCXQ BX, R13
+
+	MULXQ ·modulus+8(SB), AX, BX
+	ADOXQ AX, R13
+	ADCXQ BX, CX
+
+	MULXQ ·modulus+16(SB), AX, BX
+	ADOXQ AX, CX
+	ADCXQ BX, R8
+
+	MULXQ ·modulus+24(SB), AX, BX
+	ADOXQ AX, R8
+	ADCXQ BX, R9
+
+	MULXQ ·modulus+32(SB), AX, BX
+	ADOXQ AX, R9
+	ADCXQ BX, R10
+
+	MULXQ ·modulus+40(SB), AX, BX
+	ADOXQ AX, R10
+	ADCXQ BX, R11
+
+	// | carry from previous round and both flags into the next word
+	MOVQ $0, AX
+	ADOXQ DI, R11
+	MOVQ $0, DI
+	ADCXQ AX, DI
+	ADOXQ AX, DI
+
+/*	 | reduction					 			*/
+
+	// | R13, CX, R8, R9, R10, R11
+
+	MOVQ R13, AX
+	MOVQ CX, BX
+	MOVQ R8, DX
+	MOVQ R9, DI
+	MOVQ R10, R14
+	MOVQ R11, R15
+	SUBQ ·modulus+0(SB), AX
+	SBBQ ·modulus+8(SB), BX
+	SBBQ ·modulus+16(SB), DX
+	SBBQ ·modulus+24(SB), DI
+	SBBQ ·modulus+32(SB), R14
+	SBBQ ·modulus+40(SB), R15
+	CMOVQCC AX, R13
+	CMOVQCC BX, CX
+	CMOVQCC DX, R8
+	CMOVQCC DI, R9
+	CMOVQCC R14, R10
+	CMOVQCC R15, R11
+
+/*	 | out								 			*/
+
+	MOVQ c+0(FP), SI
+	MOVQ R13, (SI)
+	MOVQ CX, 8(SI)
+	MOVQ R8, 16(SI)
+	MOVQ R9, 24(SI)
+	MOVQ R10, 32(SI)
+	MOVQ R11, 40(SI)
+	RET
+
+/*	 | end											*/
+
+
+// double-precision addition w/ modular reduction
+// c = (a + b) % (p * R)
+TEXT ·wadd(SB), NOSPLIT, $0-24
+
+	MOVQ a+8(FP), DI
+	MOVQ b+16(FP), SI
+	MOVQ c+0(FP), DX
+
+	// | low half
+	MOVQ (DI), R8
+	MOVQ 8(DI), R9
+	MOVQ 16(DI), R10
+	MOVQ 24(DI), R11
+	MOVQ 32(DI), R12
+	MOVQ 40(DI), R13
+	ADDQ (SI), R8
+	ADCQ 8(SI), R9
+	ADCQ 16(SI), R10
+	ADCQ 24(SI), R11
+	ADCQ 32(SI), R12
+	ADCQ 40(SI), R13
+	MOVQ R8, (DX)
+	MOVQ R9, 8(DX)
+	MOVQ R10, 16(DX)
+	MOVQ R11, 24(DX)
+	MOVQ R12, 32(DX)
+	MOVQ R13, 40(DX)
+
+	// | high half
+	MOVQ 48(DI), R8
+	MOVQ 56(DI), R9
+	MOVQ 64(DI), R10
+	MOVQ 72(DI), R11
+	MOVQ 80(DI), R12
+	MOVQ 88(DI), R13
+	ADCQ 48(SI), R8
+	ADCQ 56(SI), R9
+	ADCQ 64(SI), R10
+	ADCQ 72(SI), R11
+	ADCQ 80(SI), R12
+	ADCQ 88(SI), R13
+
+	// | if c >= p * R --> c -= p * R
+	MOVQ DX, DI
+	MOVQ R8, AX
+	MOVQ R9, BX
+	MOVQ R10, CX
+	MOVQ R11, DX
+	MOVQ R12, R14
+	MOVQ R13, R15
+	SUBQ ·modulus+0(SB), AX
+	SBBQ ·modulus+8(SB), BX
+	SBBQ ·modulus+16(SB), CX
+	SBBQ ·modulus+24(SB), DX
+	SBBQ ·modulus+32(SB), R14
+	SBBQ ·modulus+40(SB), R15
+	CMOVQCC AX, R8
+	CMOVQCC BX, R9
+	CMOVQCC CX, R10
+	CMOVQCC DX, R11
+	CMOVQCC R14, R12
+	CMOVQCC R15, R13
+	MOVQ R8, 48(DI)
+	MOVQ R9, 56(DI)
+	MOVQ R10, 64(DI)
+	MOVQ R11, 72(DI)
+	MOVQ R12, 80(DI)
+	MOVQ R13, 88(DI)
+	RET
+
+// double-precision subtraction w/ modular reduction
+// c = (a - b) % (p * R)
+TEXT ·wsub(SB), NOSPLIT, $0-24
+
+	MOVQ a+8(FP), DI
+	MOVQ b+16(FP), SI
+	MOVQ c+0(FP), DX
+
+	// | low half
+	MOVQ (DI), R8
+	MOVQ 8(DI), R9
+	MOVQ 16(DI), R10
+	MOVQ 24(DI), R11
+	MOVQ 32(DI), R12
+	MOVQ 40(DI), R13
+	SUBQ (SI), R8
+	SBBQ 8(SI), R9
+	SBBQ 16(SI), R10
+	SBBQ 24(SI), R11
+	SBBQ 32(SI), R12
+	SBBQ 40(SI), R13
+	MOVQ R8, (DX)
+	MOVQ R9, 8(DX)
+	MOVQ R10, 16(DX)
+	MOVQ R11, 24(DX)
+	MOVQ R12, 32(DX)
+	MOVQ R13, 40(DX)
+
+	// | high half
+	MOVQ 48(DI), R8
+	MOVQ 56(DI), R9
+	MOVQ 64(DI), R10
+	MOVQ 72(DI), R11
+	MOVQ 80(DI), R12
+	MOVQ 88(DI), R13
+	SBBQ 48(SI), R8
+	SBBQ 56(SI), R9
+	SBBQ 64(SI), R10
+	SBBQ 72(SI), R11
+	SBBQ 80(SI), R12
+	SBBQ 88(SI), R13
+
+	// | if a < b --> c += p * R
+	SBBQ R15, R15
+	MOVQ ·modulus+0(SB), AX
+	MOVQ ·modulus+8(SB), BX
+	MOVQ ·modulus+16(SB), CX
+	MOVQ ·modulus+24(SB), SI
+	MOVQ ·modulus+32(SB), DI
+	MOVQ ·modulus+40(SB), R14
+	ANDQ R15, AX
+	ANDQ R15, BX
+	ANDQ R15, CX
+	ANDQ R15, SI
+	ANDQ R15, DI
+	ANDQ R15, R14
+	ADDQ AX, R8
+	ADCQ BX, R9
+	ADCQ CX, R10
+	ADCQ SI, R11
+	ADCQ DI, R12
+	ADCQ R14, R13
+	MOVQ R8, 48(DX)
+	MOVQ R9, 56(DX)
+	MOVQ R10, 64(DX)
+	MOVQ R11, 72(DX)
+	MOVQ R12, 80(DX)
+	MOVQ R13, 88(DX)
+	RET
@@ -35,6 +35,10 @@ var twoInv = &fe{0x1804000000015554, 0x855000053ab00001, 0x633cb57c253c276f, 0x6
 var pMinus3Over4 = new(big.Int).SetBytes(
 	fromHex(-1, "0x680447a8e5ff9a692c6e9ed90d2eb35d91dd2e13ce144afd9cc34a83dac3d8907aaffffac54ffffee7fbfffffffeaaa"))
 
+// q-2
+var pMinus2 = new(big.Int).SetBytes(
+	fromHex(-1, "0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaa9"))
+
 // (q-3) / 4
 var pPlus1Over4 = new(big.Int).SetBytes(
 	fromHex(-1, "0x680447a8e5ff9a692c6e9ed90d2eb35d91dd2e13ce144afd9cc34a83dac3d8907aaffffac54ffffee7fbfffffffeaab"))
@@ -247,6 +251,26 @@ var frobeniusCoeffs12 = [12]fe2{
 	},
 }
 
+/*
+	Endomorphisms
+*/
+
+// cube root of unity, φ(x, y) = (βx, y) acts on G1 as multiplication by -x^2
+var glvBeta = &fe{0x30f1361b798a64e8, 0xf3b8ddab7ece5a2a, 0x16a8ca3ac61577f7, 0xc26a2ff874fd029b, 0x3636b76660701c6e, 0x051ba4ab241b6160}
+
+// ψ(x, y) = (conj(x) * c1, conj(y) * c2) acts on G2 as multiplication by x
+// c1 = 1 / (u + 1)^((p - 1) / 3)
+var psiX = &fe2{
+	fe{0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000, 0x0000000000000000},
+	fe{0x890dc9e4867545c3, 0x2af322533285a5d5, 0x50880866309b7e2c, 0xa20d1b8c7e881024, 0x14e4f04fe2db9068, 0x14e56d3f1564853a},
+}
+
+// c2 = 1 / (u + 1)^((p - 1) / 2)
+var psiY = &fe2{
+	fe{0x3e2f585da55c9ad1, 0x4294213d86c18183, 0x382844c88b623732, 0x92ad2afd19103e18, 0x1d794e4fac7cf0b9, 0x0bd592fc7d825ec8},
+	fe{0x7bcfa7a25aa30fda, 0xdc17dec12a927e7c, 0x2f088dd86b4ebef1, 0xd1ca2087da74d4a7, 0x2da2596696cebc1d, 0x0e2b7eedbbfd87d2},
+}
+
 /*
 	x
 */
@@ -267,4 +291,5 @@ var cofactorEFFG2 = new(big.Int).SetBytes(
 */
 
 var forceNonADXArch bool
+var forceNonIFMAArch bool
 var x86ArchitectureSet bool = false
@@ -13,8 +13,10 @@ var fuz int
 func TestMain(m *testing.M) {
 	_fuz := flag.Int("fuzz", 10, "# of iterations")
 	adx := flag.Bool("noadx", false, "to enfoce non adx arch")
+	ifma := flag.Bool("noifma", false, "to enforce scalar batch multiplication")
 	flag.Parse()
 	forceNonADXArch = *adx
+	forceNonIFMAArch = *ifma
 	fuz = *_fuz
 	cfgArch()
 	m.Run()
@@ -0,0 +1,215 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// Elligator Squared encodes a point P as a pair of field elements (u, v) with f(u) + f(v) = P
+// where f is the simplified SWU map to the curve E' that is 11-isogenous to G1 curve.
+// For a uniformly random P encoding is statistically close to a uniform pair.
+// https://eprint.iacr.org/2014/043
+//
+// G1 point is first taken to E' with the inverse of the isogeny and randomized with a uniform
+// element of the subgroup of order h, the cofactor. Decoding maps f(u) + f(v) back with the isogeny
+// and removes the randomization multiplying by h * (h^-1 mod q) which is one modulo q and zero modulo h.
+
+// uniformBytesMaxPreimages bounds the number of preimages of a point under swuMapG1.
+const uniformBytesMaxPreimages = 6
+
+// h * (h^-1 mod q)
+var cofactorProjectionG1 = new(big.Int).SetBytes(
+	fromHex(-1, "0x1a0111ea397fe6998ce8d956845e10354b6cb56f9c3e9ac6b0056eb4f5bd77adc76f13fa9d5cec01e8000002fffeaaa9"))
+
+// ToUniformBytes encodes a G1 point to 96 bytes which are indistinguishable from uniformly random bytes
+// when the point is uniformly random. Randomness is drawn from given reader and if it is nil crypto/rand is used.
+// Each half of the output is a field element with a random multiple of the modulus added
+// so that it is uniform in 48 bytes. Encoding is not constant time and since inverting the isogeny
+// needs a polynomial root finding it is much slower than decoding.
+func (g *G1) ToUniformBytes(p *PointG1, r io.Reader) ([]byte, error) {
+	if r == nil {
+		r = rand.Reader
+	}
+	if !g.InCorrectSubgroup(p) {
+		return nil, fmt.Errorf("point is not in correct subgroup")
+	}
+	target := &isoPointG1{infinity: true}
+	if !g.IsZero(p) {
+		a := g.Affine(g.New().Set(p))
+		x, y, err := isogenyMapG1Inverse(&a[0], &a[1])
+		if err != nil {
+			return nil, err
+		}
+		target.x, target.y, target.infinity = *x, *y, false
+	}
+	t, err := randIsoPointG1(r)
+	if err != nil {
+		return nil, err
+	}
+	t.mul(t, q)
+	target.add(target, t)
+	modulusBig, bound := modulus.big(), new(big.Int).Lsh(big.NewInt(1), 384)
+	out, d := make([]byte, 96), new(isoPointG1)
+	for {
+		// u is reduced from uniform bytes, so first half is uniform as it is
+		if _, err := io.ReadFull(r, out[:48]); err != nil {
+			return nil, err
+		}
+		u, _ := fromBig(new(big.Int).Mod(new(big.Int).SetBytes(out[:48]), modulusBig))
+		x, y := swuMapG1CT(u)
+		d.set(&isoPointG1{x: *x, y: *y})
+		d.neg(d)
+		d.add(target, d)
+		if d.infinity {
+			continue
+		}
+		// pick one of k preimages with probability k / N
+		preimages := swuMapG1Inverse(&d.x, &d.y)
+		j, err := rand.Int(r, big.NewInt(uniformBytesMaxPreimages))
+		if err != nil {
+			return nil, err
+		}
+		if j.Int64() >= int64(len(preimages)) {
+			continue
+		}
+		// v + k * p is uniform in 48 bytes if it is rejected when it overflows
+		k, err := rand.Int(r, big.NewInt(10))
+		if err != nil {
+			return nil, err
+		}
+		v := toBig(preimages[j.Int64()])
+		v.Add(v, k.Mul(k, modulusBig))
+		if v.Cmp(bound) >= 0 {
+			continue
+		}
+		v.FillBytes(out[48:])
+		return out, nil
+	}
+}
+
+// FromUniformBytes decodes 96 bytes to a G1 point. Any input is decoded to a point in correct subgroup
+// and the output of ToUniformBytes is decoded to the encoded point.
+func (g *G1) FromUniformBytes(in []byte) (*PointG1, error) {
+	if len(in) != 96 {
+		return nil, fmt.Errorf("input string should be 96 bytes")
+	}
+	modulusBig := modulus.big()
+	u, _ := fromBig(new(big.Int).Mod(new(big.Int).SetBytes(in[:48]), modulusBig))
+	v, _ := fromBig(new(big.Int).Mod(new(big.Int).SetBytes(in[48:]), modulusBig))
+	x0, y0 := swuMapG1CT(u)
+	x1, y1 := swuMapG1CT(v)
+	s := &isoPointG1{x: *x0, y: *y0}
+	s.add(s, &isoPointG1{x: *x1, y: *y1})
+	if s.infinity {
+		return g.Zero(), nil
+	}
+	// points in the kernel of the isogeny are mapped to infinity
+	p := g.New()
+	isogenyMapG1Projective(p, &s.x, &s.y)
+	g.MulScalar(p, g.affineCT(p), cofactorProjectionG1)
+	return g.Affine(p), nil
+}
+
+// isoPointG1 is an affine point on the curve y^2 = x^3 + A' * x + B' that swuMapG1 maps to.
+// Arithmetic of G1 assumes A = 0 so it is not used for these points.
+type isoPointG1 struct {
+	x, y     fe
+	infinity bool
+}
+
+// randIsoPointG1 returns a uniformly random affine point of the isogenous curve.
+// Curve order is odd so there is no point with y = 0 and both roots are taken with the same probability.
+func randIsoPointG1(r io.Reader) (*isoPointG1, error) {
+	params := swuParamsForG1
+	p, rhs := new(isoPointG1), new(fe)
+	sign := make([]byte, 1)
+	for {
+		x, err := newRand(r)
+		if err != nil {
+			return nil, err
+		}
+		// y^2 = x^3 + A' * x + B'
+		square(rhs, x)
+		add(rhs, rhs, params.a)
+		mul(rhs, rhs, x)
+		add(rhs, rhs, params.b)
+		if !sqrt(&p.y, rhs) {
+			continue
+		}
+		if _, err := io.ReadFull(r, sign); err != nil {
+			return nil, err
+		}
+		if sign[0]&1 == 1 {
+			neg(&p.y, &p.y)
+		}
+		p.x.set(x)
+		return p, nil
+	}
+}
+
+func (p *isoPointG1) set(a *isoPointG1) *isoPointG1 {
+	*p = *a
+	return p
+}
+
+func (p *isoPointG1) neg(a *isoPointG1) *isoPointG1 {
+	p.set(a)
+	neg(&p.y, &a.y)
+	return p
+}
+
+// add sets p = a + b with affine formulas.
+func (p *isoPointG1) add(a, b *isoPointG1) *isoPointG1 {
+	if a.infinity {
+		return p.set(b)
+	}
+	if b.infinity {
+		return p.set(a)
+	}
+	l, t := new(fe), new(fe)
+	if equal(&a.x, &b.x) {
+		add(t, &a.y, &b.y)
+		if t.isZero() {
+			*p = isoPointG1{infinity: true}
+			return p
+		}
+		// l = (3 * x^2 + A') / (2 * y)
+		square(l, &a.x)
+		double(t, l)
+		add(l, l, t)
+		add(l, l, swuParamsForG1.a)
+		double(t, &a.y)
+	} else {
+		// l = (y1 - y0) / (x1 - x0)
+		sub(l, &b.y, &a.y)
+		sub(t, &b.x, &a.x)
+	}
+	inverse(t, t)
+	mul(l, l, t)
+	// x = l^2 - x0 - x1, y = l * (x0 - x) - y0
+	x, y := new(fe), new(fe)
+	square(x, l)
+	sub(x, x, &a.x)
+	sub(x, x, &b.x)
+	sub(y, &a.x, x)
+	mul(y, y, l)
+	sub(y, y, &a.y)
+	p.x.set(x)
+	p.y.set(y)
+	p.infinity = false
+	return p
+}
+
+// mul sets p = e * a with double and add.
+func (p *isoPointG1) mul(a *isoPointG1, e *big.Int) *isoPointG1 {
+	r, n := &isoPointG1{infinity: true}, new(isoPointG1).set(a)
+	for i := 0; i < e.BitLen(); i++ {
+		if e.Bit(i) == 1 {
+			r.add(r, n)
+		}
+		n.add(n, n)
+	}
+	return p.set(r)
+}
@@ -11,6 +11,11 @@ type fe2 /**			***/ [2]fe
 type fe6 /**			***/ [3]fe2
 type fe12 /**			***/ [2]fe6
 
+// wfe is double-width field element for unreduced products.
+type wfe /***			***/ [12]uint64
+type wfe2 /**			***/ [2]wfe
+type wfe6 /**			***/ [3]wfe2
+
 func (fe *fe) bytes() []byte {
 	out := make([]byte, 48)
 	var a int
@@ -204,6 +204,39 @@ func inverse(inv, e *fe) {
 	return
 }
 
+// inverseCT is Fermat inversion that does not branch on the input.
+// Zero is mapped to zero.
+func inverseCT(inv, e *fe) {
+	exp(inv, e, pMinus2)
+}
+
+// cmov sets c to b if cond is 1 and to a if cond is 0 without branching on cond.
+func cmov(c, a, b *fe, cond uint64) {
+	mask := -cond
+	for i := 0; i < 6; i++ {
+		c[i] = a[i] ^ (mask & (a[i] ^ b[i]))
+	}
+}
+
+// isZeroCT returns 1 if a is zero and 0 otherwise without branching.
+func isZeroCT(a *fe) uint64 {
+	t := a[0] | a[1] | a[2] | a[3] | a[4] | a[5]
+	return 1 ^ ((t | -t) >> 63)
+}
+
+// equalCT returns 1 if a and b are equal and 0 otherwise without branching.
+func equalCT(a, b *fe) uint64 {
+	t := (a[0] ^ b[0]) | (a[1] ^ b[1]) | (a[2] ^ b[2]) | (a[3] ^ b[3]) | (a[4] ^ b[4]) | (a[5] ^ b[5])
+	return 1 ^ ((t | -t) >> 63)
+}
+
+// sgn0 returns the parity of a as defined in RFC 9380 section 4.1.
+func sgn0(a *fe) uint64 {
+	r := new(fe)
+	fromMont(r, a)
+	return r[0] & 1
+}
+
 func sqrt(c, a *fe) (hasRoot bool) {
 	u, v := new(fe).set(a), new(fe)
 	exp(c, a, pPlus1Over4)
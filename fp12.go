@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"math/bits"
 )
 
 type fp12 struct {
@@ -15,6 +16,7 @@ type fp12temp struct {
 	t2  [9]*fe2
 	t6  [5]*fe6
 	t12 *fe12
+	wt  [3]*wfe6
 }
 
 func newFp12Temp() fp12temp {
@@ -26,7 +28,11 @@ func newFp12Temp() fp12temp {
 	for i := 0; i < len(t6); i++ {
 		t6[i] = &fe6{}
 	}
-	return fp12temp{t2, t6, &fe12{}}
+	wt := [3]*wfe6{}
+	for i := 0; i < len(wt); i++ {
+		wt[i] = &wfe6{}
+	}
+	return fp12temp{t2, t6, &fe12{}, wt}
 }
 
 func newFp12(fp6 *fp6) *fp12 {
@@ -178,32 +184,119 @@ func (e *fp12) cyclotomicSquare(c, a *fe12) {
 	fp2.add(&c[0][2], t[2], t[5])
 }
 
+// cyclotomicSquareCompressed squares an element of the cyclotomic subgroup
+// in Karabina's compressed form. Only g1 = a[0][1], g2 = a[0][2], g3 = a[1][0]
+// and g5 = a[1][2] are updated, g0 and g4 are recovered by cyclotomicDecompress.
+// https://eprint.iacr.org/2010/542.pdf
+func (e *fp12) cyclotomicSquareCompressed(c, a *fe12) {
+	t, fp2 := e.t2, e.fp2()
+	// t0 = g1^2, t1 = g5^2
+	fp2.square(t[0], &a[0][1])
+	fp2.square(t[1], &a[1][2])
+	// t5 = 2 * g1 * g5
+	fp2.add(t[5], &a[0][1], &a[1][2])
+	fp2.square(t[2], t[5])
+	fp2.add(t[3], t[0], t[1])
+	fp2.sub(t[5], t[2], t[3])
+	// t3 = (g3 + g2)^2, t2 = g3^2
+	fp2.add(t[6], &a[1][0], &a[0][2])
+	fp2.square(t[3], t[6])
+	fp2.square(t[2], &a[1][0])
+	// h3 = 6 * nr * g1 * g5 + 2 * g3
+	fp2.mulByNonResidue(t[6], t[5])
+	fp2.add(t[5], t[6], &a[1][0])
+	fp2.doubleAssign(t[5])
+	fp2.add(&c[1][0], t[5], t[6])
+	// h2 = 3 * (nr * g5^2 + g1^2) - 2 * g2
+	fp2.mulByNonResidue(t[4], t[1])
+	fp2.add(t[5], t[0], t[4])
+	fp2.sub(t[6], t[5], &a[0][2])
+	fp2.square(t[1], &a[0][2])
+	fp2.doubleAssign(t[6])
+	fp2.add(&c[0][2], t[6], t[5])
+	// h1 = 3 * (g3^2 + nr * g2^2) - 2 * g1
+	fp2.mulByNonResidue(t[4], t[1])
+	fp2.add(t[5], t[2], t[4])
+	fp2.sub(t[6], t[5], &a[0][1])
+	fp2.doubleAssign(t[6])
+	fp2.add(&c[0][1], t[6], t[5])
+	// h5 = 6 * g3 * g2 + 2 * g5
+	fp2.add(t[0], t[2], t[1])
+	fp2.sub(t[5], t[3], t[0])
+	fp2.add(t[6], t[5], &a[1][2])
+	fp2.doubleAssign(t[6])
+	fp2.add(&c[1][2], t[5], t[6])
+}
+
+// cyclotomicDecompress recovers g0 and g4 of elements squared in compressed form.
+// Denominators of all elements are inverted at once.
+// If g2 = g3 = 0 for any of the elements g4 cannot be recovered,
+// then false is returned and elements are not modified.
+func (e *fp12) cyclotomicDecompress(a []fe12) bool {
+	fp2, t := e.fp2(), e.t2
+	num, den := make([]fe2, len(a)), make([]fe2, len(a))
+	for i := range a {
+		g1, g2, g3, g5 := &a[i][0][1], &a[i][0][2], &a[i][1][0], &a[i][1][2]
+		if fp2.isZero(g2) && fp2.isZero(g3) {
+			return false
+		}
+		if !fp2.isZero(g3) {
+			// g4 = (nr * g5^2 + 3 * g1^2 - 2 * g2) / 4 * g3
+			fp2.square(t[0], g1)
+			fp2.sub(t[1], t[0], g2)
+			fp2.doubleAssign(t[1])
+			fp2.add(t[1], t[1], t[0])
+			fp2.square(t[2], g5)
+			fp2.mulByNonResidue(t[0], t[2])
+			fp2.add(&num[i], t[0], t[1])
+			fp2.double(&den[i], g3)
+			fp2.doubleAssign(&den[i])
+		} else {
+			// g4 = 2 * g1 * g5 / g2
+			fp2.mul(&num[i], g1, g5)
+			fp2.doubleAssign(&num[i])
+			fp2.copy(&den[i], g2)
+		}
+	}
+	fp2.inverseBatch(den)
+	for i := range a {
+		g1, g2, g3, g5 := &a[i][0][1], &a[i][0][2], &a[i][1][0], &a[i][1][2]
+		fp2.mul(&a[i][1][1], &num[i], &den[i])
+		// g0 = nr * (2 * g4^2 + g3 * g5 - 3 * g2 * g1) + 1
+		fp2.mul(t[1], g2, g1)
+		fp2.square(t[2], &a[i][1][1])
+		fp2.sub(t[2], t[2], t[1])
+		fp2.doubleAssign(t[2])
+		fp2.sub(t[2], t[2], t[1])
+		fp2.mul(t[1], g3, g5)
+		fp2.add(t[2], t[2], t[1])
+		fp2.mulByNonResidue(&a[i][0][0], t[2])
+		fp2.add(&a[i][0][0], &a[i][0][0], fp2.one())
+	}
+	return true
+}
+
 func (e *fp12) mul(c, a, b *fe12) {
-	t, fp6 := e.t6, e.fp6
-	fp6.mul(t[1], &a[0], &b[0])
-	fp6.mul(t[2], &a[1], &b[1])
-	fp6.add(t[0], t[1], t[2])
-	fp6.mulByNonResidue(t[2], t[2])
-	fp6.add(t[3], t[1], t[2])
-	fp6.add(t[1], &a[0], &a[1])
-	fp6.add(t[2], &b[0], &b[1])
-	fp6.mulAssign(t[1], t[2])
-	fp6.copy(&c[0], t[3])
-	fp6.sub(&c[1], t[1], t[0])
+	fp2, fp6, t, wt := e.fp2(), e.fp6, e.t6, e.wt
+	fp6.wmul(wt[0], &a[0], &b[0])
+	fp6.wmul(wt[1], &a[1], &b[1])
+	fp6.add(t[0], &a[0], &a[1])
+	fp6.add(t[1], &b[0], &b[1])
+	fp6.wmul(wt[2], t[0], t[1])
+	// c1 = (a0 + a1)(b0 + b1) - v0 - v1
+	fp6.wsub(wt[2], wt[2], wt[0])
+	fp6.wsub(wt[2], wt[2], wt[1])
+	// c0 = v0 + v1 * nonresidue
+	fp2.wmulByNonResidue(&wt[1][2], &wt[1][2])
+	fp2.wadd(&wt[0][0], &wt[0][0], &wt[1][2])
+	fp2.wadd(&wt[0][1], &wt[0][1], &wt[1][0])
+	fp2.wadd(&wt[0][2], &wt[0][2], &wt[1][1])
+	fp6.montRed(&c[0], wt[0])
+	fp6.montRed(&c[1], wt[2])
 }
 
 func (e *fp12) mulAssign(a, b *fe12) {
-	t, fp6 := e.t6, e.fp6
-	fp6.mul(t[1], &a[0], &b[0])
-	fp6.mul(t[2], &a[1], &b[1])
-	fp6.add(t[0], t[1], t[2])
-	fp6.mulByNonResidue(t[2], t[2])
-	fp6.add(t[3], t[1], t[2])
-	fp6.add(t[1], &a[0], &a[1])
-	fp6.add(t[2], &b[0], &b[1])
-	fp6.mulAssign(t[1], t[2])
-	fp6.copy(&a[0], t[3])
-	fp6.sub(&a[1], t[1], t[0])
+	e.mul(a, a, b)
 }
 
 func (e *fp12) fp4Square(c0, c1, a0, a1 *fe2) {
@@ -243,6 +336,22 @@ func (e *fp12) mulBy014Assign(a *fe12, c0, c1, c4 *fe2) {
 	fp6.add(&a[0], t[1], t[0])
 }
 
+// mulBy014OneAssign is mulBy014Assign with c4 = 1,
+// where multiplication of the second half by c4 turns into multiplication by non residue.
+func (e *fp12) mulBy014OneAssign(a *fe12, c0, c1 *fe2) {
+	fp6, t, t2 := e.fp6, e.t6, e.t2[0]
+	fp6.mulBy01(t[0], &a[0], c0, c1)
+	fp6.mulByNonResidue(t[1], &a[1])
+	add(&t2[0], &c1[0], r1)
+	t2[1].set(&c1[1])
+	fp6.add(t[2], &a[1], &a[0])
+	fp6.mulBy01Assign(t[2], c0, t2)
+	fp6.subAssign(t[2], t[0])
+	fp6.sub(&a[1], t[2], t[1])
+	fp6.mulByNonResidue(t[1], t[1])
+	fp6.add(&a[0], t[1], t[0])
+}
+
 func (e *fp12) exp(c, a *fe12, s *big.Int) {
 	z := e.one()
 	for i := s.BitLen() - 1; i >= 0; i-- {
@@ -255,6 +364,9 @@ func (e *fp12) exp(c, a *fe12, s *big.Int) {
 }
 
 func (e *fp12) cyclotomicExp(c, a *fe12, s *big.Int) {
+	if isSparseExponent(s) && e.cyclotomicExpCompressed(c, a, s) {
+		return
+	}
 	z := e.one()
 	for i := s.BitLen() - 1; i >= 0; i-- {
 		e.cyclotomicSquare(z, z)
@@ -265,6 +377,122 @@ func (e *fp12) cyclotomicExp(c, a *fe12, s *big.Int) {
 	e.copy(c, z)
 }
 
+// gtDecompose reduces the scalar modulo q and writes it in base |x| with four 64 bit digits.
+func gtDecompose(s *big.Int) [4]uint64 {
+	k := new(big.Int).Mod(s, q)
+	d := new(big.Int)
+	var digits [4]uint64
+	for i := 0; i < 4; i++ {
+		k.DivMod(k, x, d)
+		digits[i] = d.Uint64()
+	}
+	return digits
+}
+
+// gtBases sets c_i = a^(|x|^i) for an element of order q subgroup.
+// Since x is negative a^|x| = a^-p and a^(|x|^3) = a^(-p^3).
+func (e *fp12) gtBases(c0, c1, c2, c3, a *fe12) {
+	e.frobeniusMap(c1, a, 1)
+	e.conjugate(c1, c1)
+	e.frobeniusMap(c2, a, 2)
+	e.frobeniusMap(c3, a, 3)
+	e.conjugate(c3, c3)
+	e.copy(c0, a)
+}
+
+// gtExp exponentiates an element of order q subgroup. Frobenius map acts as exponentiation by p and p = x mod q,
+// so scalar is written in base |x| as s = s0 + s1 * |x| + s2 * |x|^2 + s3 * |x|^3 and four 64 bit
+// exponentiations with bases a^(|x|^i) are evaluated jointly.
+func (e *fp12) gtExp(c, a *fe12, s *big.Int) {
+	e.gtExpJoint(c, []*fe12{a}, []*big.Int{s})
+}
+
+// gtExpJoint calculates a_0^s_0 * a_1^s_1 * ... * a_n^s_n for elements of order q subgroup
+// sharing squarings of all decomposed exponentiations.
+func (e *fp12) gtExpJoint(c *fe12, a []*fe12, s []*big.Int) {
+	// tables[16 * i + j] is the product of bases of a_i selected by bits of j
+	tables := make([]fe12, 16*len(a))
+	digits := make([][4]uint64, len(a))
+	for i := range a {
+		digits[i] = gtDecompose(s[i])
+		table := tables[16*i : 16*i+16]
+		e.gtBases(&table[1], &table[2], &table[4], &table[8], a[i])
+		for j := 3; j < 16; j++ {
+			if j&(j-1) == 0 {
+				continue
+			}
+			lo := j & -j
+			e.mul(&table[j], &table[j-lo], &table[lo])
+		}
+	}
+	z := e.one()
+	for b := uint(63); b < 64; b-- {
+		e.cyclotomicSquare(z, z)
+		for i := range digits {
+			d := &digits[i]
+			j := (d[0]>>b)&1 | ((d[1]>>b)&1)<<1 | ((d[2]>>b)&1)<<2 | ((d[3]>>b)&1)<<3
+			if j != 0 {
+				e.mul(z, z, &tables[16*i+int(j)])
+			}
+		}
+	}
+	e.copy(c, z)
+}
+
+// cyclotomicExpCompressed scans the exponent from the least significant bit with
+// compressed squarings and decompresses the squares that are needed for the product.
+// It returns false and leaves c unchanged if a square cannot be decompressed.
+func (e *fp12) cyclotomicExpCompressed(c, a *fe12, s *big.Int) bool {
+	z, r := e.new(), e.one()
+	e.copy(z, a)
+	if s.Bit(0) == 1 {
+		e.copy(r, a)
+	}
+	squares := make([]fe12, 0, 8)
+	for i := 1; i < s.BitLen(); i++ {
+		e.cyclotomicSquareCompressed(z, z)
+		if s.Bit(i) == 1 {
+			squares = append(squares, *z)
+		}
+	}
+	if !e.cyclotomicDecompress(squares) {
+		return false
+	}
+	for i := range squares {
+		e.mul(r, r, &squares[i])
+	}
+	e.copy(c, r)
+	return true
+}
+
+// torusCompress maps an element a = a0 + a1 * w of cyclotomic subgroup to c = (1 + a0) / a1
+// and returns false if a1 is zero, that is for elements 1 and -1.
+func (e *fp12) torusCompress(c *fe6, a *fe12) bool {
+	fp6 := e.fp6
+	if fp6.isZero(&a[1]) {
+		return false
+	}
+	t := fp6.new()
+	fp6.inverse(t, &a[1])
+	fp6.add(c, &a[0], fp6.one())
+	fp6.mul(c, c, t)
+	return true
+}
+
+// torusDecompress maps c to (c + w) / (c - w) = (c^2 + v + 2 * c * w) / (c^2 - v).
+func (e *fp12) torusDecompress(c *fe12, a *fe6) {
+	fp6 := e.fp6
+	t0, t1, v := fp6.new(), fp6.new(), fp6.zero()
+	v[1][0].one()
+	fp6.square(t0, a)
+	fp6.sub(t1, t0, v)
+	fp6.inverse(t1, t1)
+	fp6.add(&c[0], t0, v)
+	fp6.mul(&c[0], &c[0], t1)
+	fp6.double(&c[1], a)
+	fp6.mul(&c[1], &c[1], t1)
+}
+
 func (e *fp12) frobeniusMap(c, a *fe12, power uint) {
 	fp6 := e.fp6
 	fp6.frobeniusMap(&c[0], &a[0], power)
@@ -292,3 +520,13 @@ func (e *fp12) frobeniusMapAssign(a *fe12, power uint) {
 		fp6.mulByBaseField(&a[1], &a[1], &frobeniusCoeffs12[power])
 	}
 }
+
+// isSparseExponent tells if compressed squarings pay off for the exponent.
+// Each set bit costs a decompression, so the exponent should be mostly zeros.
+func isSparseExponent(s *big.Int) bool {
+	w := 0
+	for _, word := range s.Bits() {
+		w += bits.OnesCount(uint(word))
+	}
+	return 3*w <= s.BitLen()
+}
@@ -7,7 +7,9 @@ import (
 )
 
 type fp2Temp struct {
-	t [4]*fe
+	t  [4]*fe
+	wt [2]*wfe
+	w  *wfe2
 }
 
 type fp2 struct {
@@ -19,7 +21,11 @@ func newFp2Temp() fp2Temp {
 	for i := 0; i < len(t); i++ {
 		t[i] = &fe{}
 	}
-	return fp2Temp{t}
+	wt := [2]*wfe{}
+	for i := 0; i < len(wt); i++ {
+		wt[i] = &wfe{}
+	}
+	return fp2Temp{t, wt, &wfe2{}}
 }
 
 func newFp2() *fp2 {
@@ -147,27 +153,50 @@ func (e *fp2) conjugate(c, a *fe2) {
 }
 
 func (e *fp2) mul(c, a, b *fe2) {
-	t := e.t
-	mul(t[1], &a[0], &b[0])
-	mul(t[2], &a[1], &b[1])
-	add(t[0], &a[0], &a[1])
-	add(t[3], &b[0], &b[1])
-	sub(&c[0], t[1], t[2])
-	addAssign(t[1], t[2])
-	mulAssign(t[0], t[3])
-	sub(&c[1], t[0], t[1])
+	w := e.w
+	e.wmul(w, a, b)
+	e.montRed(c, w)
 }
 
 func (e *fp2) mulAssign(a, b *fe2) {
-	t := e.t
-	mul(t[1], &a[0], &b[0])
-	mul(t[2], &a[1], &b[1])
-	add(t[0], &a[0], &a[1])
-	add(t[3], &b[0], &b[1])
-	sub(&a[0], t[1], t[2])
-	addAssign(t[1], t[2])
-	mulAssign(t[0], t[3])
-	sub(&a[1], t[0], t[1])
+	w := e.w
+	e.wmul(w, a, b)
+	e.montRed(a, w)
+}
+
+// wmul multiplies `a` and `b` and sets the double-width result `c` without reduction
+func (e *fp2) wmul(c *wfe2, a, b *fe2) {
+	t, wt := e.t, e.wt
+	wmul(&c[0], &a[0], &b[0])
+	wmul(wt[0], &a[1], &b[1])
+	ladd(t[0], &a[0], &a[1])
+	ladd(t[1], &b[0], &b[1])
+	wmul(&c[1], t[0], t[1])
+	wsub(&c[1], &c[1], &c[0])
+	wsub(&c[1], &c[1], wt[0])
+	wsub(&c[0], &c[0], wt[0])
+}
+
+func (e *fp2) montRed(c *fe2, a *wfe2) {
+	montRed(&c[0], &a[0])
+	montRed(&c[1], &a[1])
+}
+
+func (e *fp2) wadd(c, a, b *wfe2) {
+	wadd(&c[0], &a[0], &b[0])
+	wadd(&c[1], &a[1], &b[1])
+}
+
+func (e *fp2) wsub(c, a, b *wfe2) {
+	wsub(&c[0], &a[0], &b[0])
+	wsub(&c[1], &a[1], &b[1])
+}
+
+func (e *fp2) wmulByNonResidue(c, a *wfe2) {
+	wt := e.wt
+	wsub(wt[0], &a[0], &a[1])
+	wadd(&c[1], &a[0], &a[1])
+	c[0] = *wt[0]
 }
 
 func (e *fp2) square(c, a *fe2) {
@@ -216,6 +245,62 @@ func (e *fp2) inverse(c, a *fe2) {
 	neg(&c[1], t[0])
 }
 
+// inverseBatch replaces each element with its inverse using Montgomery's trick.
+// Zero elements are left as zero.
+func (e *fp2) inverseBatch(a []fe2) {
+	if len(a) == 0 {
+		return
+	}
+	prefix := make([]fe2, len(a))
+	acc, t := e.one(), e.new()
+	for i := range a {
+		e.copy(&prefix[i], acc)
+		if !e.isZero(&a[i]) {
+			e.mul(acc, acc, &a[i])
+		}
+	}
+	e.inverse(acc, acc)
+	for i := len(a) - 1; i >= 0; i-- {
+		if e.isZero(&a[i]) {
+			continue
+		}
+		e.mul(t, acc, &prefix[i])
+		e.mul(acc, acc, &a[i])
+		e.copy(&a[i], t)
+	}
+}
+
+// inverseCT is the same as inverse but it does not branch on the input.
+func (e *fp2) inverseCT(c, a *fe2) {
+	t := e.t
+	square(t[0], &a[0])
+	square(t[1], &a[1])
+	addAssign(t[0], t[1])
+	inverseCT(t[0], t[0])
+	mul(&c[0], &a[0], t[0])
+	mulAssign(t[0], &a[1])
+	sub(&c[1], zero(), t[0])
+}
+
+func (e *fp2) cmov(c, a, b *fe2, cond uint64) {
+	cmov(&c[0], &a[0], &b[0], cond)
+	cmov(&c[1], &a[1], &b[1], cond)
+}
+
+func (e *fp2) isZeroCT(a *fe2) uint64 {
+	return isZeroCT(&a[0]) & isZeroCT(&a[1])
+}
+
+func (e *fp2) equalCT(a, b *fe2) uint64 {
+	return equalCT(&a[0], &b[0]) & equalCT(&a[1], &b[1])
+}
+
+// sgn0 returns the sign of a as defined for extension fields in RFC 9380 section 4.1.
+func (e *fp2) sgn0(a *fe2) uint64 {
+	sign0, sign1 := sgn0(&a[0]), sgn0(&a[1])
+	return sign0 | (isZeroCT(&a[0]) & sign1)
+}
+
 func (e *fp2) mulByFq(c, a *fe2, b *fe) {
 	mul(&c[0], &a[0], b)
 	mul(&c[1], &a[1], b)
@@ -7,7 +7,9 @@ import (
 )
 
 type fp6Temp struct {
-	t [6]*fe2
+	t  [6]*fe2
+	wt [5]*wfe2
+	w  *wfe6
 }
 
 type fp6 struct {
@@ -20,7 +22,11 @@ func newFp6Temp() fp6Temp {
 	for i := 0; i < len(t); i++ {
 		t[i] = &fe2{}
 	}
-	return fp6Temp{t}
+	wt := [5]*wfe2{}
+	for i := 0; i < len(wt); i++ {
+		wt[i] = &wfe2{}
+	}
+	return fp6Temp{t, wt, &wfe6{}}
 }
 
 func newFp6(f *fp2) *fp6 {
@@ -164,59 +170,67 @@ func (e *fp6) conjugate(c, a *fe6) {
 }
 
 func (e *fp6) mul(c, a, b *fe6) {
-	fp2, t := e.fp2, e.t
-	fp2.mul(t[0], &a[0], &b[0])
-	fp2.mul(t[1], &a[1], &b[1])
-	fp2.mul(t[2], &a[2], &b[2])
-	fp2.add(t[3], &a[1], &a[2])
-	fp2.add(t[4], &b[1], &b[2])
-	fp2.mulAssign(t[3], t[4])
-	fp2.add(t[4], t[1], t[2])
-	fp2.subAssign(t[3], t[4])
-	fp2.mulByNonResidue(t[3], t[3])
-	fp2.add(t[5], t[0], t[3])
-	fp2.add(t[3], &a[0], &a[1])
-	fp2.add(t[4], &b[0], &b[1])
-	fp2.mulAssign(t[3], t[4])
-	fp2.add(t[4], t[0], t[1])
-	fp2.subAssign(t[3], t[4])
-	fp2.mulByNonResidue(t[4], t[2])
-	fp2.add(&c[1], t[3], t[4])
-	fp2.add(t[3], &a[0], &a[2])
-	fp2.add(t[4], &b[0], &b[2])
-	fp2.mulAssign(t[3], t[4])
-	fp2.add(t[4], t[0], t[2])
-	fp2.subAssign(t[3], t[4])
-	fp2.add(&c[2], t[1], t[3])
-	fp2.copy(&c[0], t[5])
+	w := e.w
+	e.wmul(w, a, b)
+	e.montRed(c, w)
 }
 
 func (e *fp6) mulAssign(a, b *fe6) {
-	fp2, t := e.fp2, e.t
-	fp2.mul(t[0], &a[0], &b[0])
-	fp2.mul(t[1], &a[1], &b[1])
-	fp2.mul(t[2], &a[2], &b[2])
-	fp2.add(t[3], &a[1], &a[2])
-	fp2.add(t[4], &b[1], &b[2])
-	fp2.mulAssign(t[3], t[4])
-	fp2.add(t[4], t[1], t[2])
-	fp2.subAssign(t[3], t[4])
-	fp2.mulByNonResidue(t[3], t[3])
-	fp2.add(t[5], t[0], t[3])
-	fp2.add(t[3], &a[0], &a[1])
-	fp2.add(t[4], &b[0], &b[1])
-	fp2.mulAssign(t[3], t[4])
-	fp2.add(t[4], t[0], t[1])
-	fp2.subAssign(t[3], t[4])
-	fp2.mulByNonResidue(t[4], t[2])
-	fp2.add(&a[1], t[3], t[4])
-	fp2.add(t[3], &a[0], &a[2])
-	fp2.add(t[4], &b[0], &b[2])
-	fp2.mulAssign(t[3], t[4])
-	fp2.add(t[4], t[0], t[2])
-	fp2.subAssign(t[3], t[4])
-	fp2.add(&a[2], t[1], t[3])
-	fp2.copy(&a[0], t[5])
+	w := e.w
+	e.wmul(w, a, b)
+	e.montRed(a, w)
+}
+
+// wmul multiplies `a` and `b` and sets the double-width result `c` without reduction
+func (e *fp6) wmul(c *wfe6, a, b *fe6) {
+	fp2, t, wt := e.fp2, e.t, e.wt
+	fp2.wmul(wt[0], &a[0], &b[0])
+	fp2.wmul(wt[1], &a[1], &b[1])
+	fp2.wmul(wt[2], &a[2], &b[2])
+	// c0 = v0 + ((a1 + a2)(b1 + b2) - v1 - v2) * nonresidue
+	fp2.add(t[0], &a[1], &a[2])
+	fp2.add(t[1], &b[1], &b[2])
+	fp2.wmul(wt[3], t[0], t[1])
+	fp2.wsub(wt[3], wt[3], wt[1])
+	fp2.wsub(wt[3], wt[3], wt[2])
+	fp2.wmulByNonResidue(wt[3], wt[3])
+	fp2.wadd(&c[0], wt[3], wt[0])
+	// c1 = (a0 + a1)(b0 + b1) - v0 - v1 + v2 * nonresidue
+	fp2.add(t[0], &a[0], &a[1])
+	fp2.add(t[1], &b[0], &b[1])
+	fp2.wmul(wt[3], t[0], t[1])
+	fp2.wsub(wt[3], wt[3], wt[0])
+	fp2.wsub(wt[3], wt[3], wt[1])
+	fp2.wmulByNonResidue(wt[4], wt[2])
+	fp2.wadd(&c[1], wt[3], wt[4])
+	// c2 = (a0 + a2)(b0 + b2) - v0 - v2 + v1
+	fp2.add(t[0], &a[0], &a[2])
+	fp2.add(t[1], &b[0], &b[2])
+	fp2.wmul(wt[3], t[0], t[1])
+	fp2.wsub(wt[3], wt[3], wt[0])
+	fp2.wsub(wt[3], wt[3], wt[2])
+	fp2.wadd(&c[2], wt[3], wt[1])
+}
+
+func (e *fp6) montRed(c *fe6, a *wfe6) {
+	fp2 := e.fp2
+	fp2.montRed(&c[0], &a[0])
+	fp2.montRed(&c[1], &a[1])
+	fp2.montRed(&c[2], &a[2])
+}
+
+func (e *fp6) wadd(c, a, b *wfe6) {
+	fp2 := e.fp2
+	fp2.wadd(&c[0], &a[0], &b[0])
+	fp2.wadd(&c[1], &a[1], &b[1])
+	fp2.wadd(&c[2], &a[2], &b[2])
+}
+
+func (e *fp6) wsub(c, a, b *wfe6) {
+	fp2 := e.fp2
+	fp2.wsub(&c[0], &a[0], &b[0])
+	fp2.wsub(&c[1], &a[1], &b[1])
+	fp2.wsub(&c[2], &a[2], &b[2])
 }
 
 func (e *fp6) square(c, a *fe6) {
@@ -348,9 +362,11 @@ func (e *fp6) frobeniusMap(c, a *fe6, power uint) {
 	case 0:
 		return
 	case 3:
-		neg(&c[0][0], &a[1][1])
-		c[1][1].set(&a[1][0])
-		fp2.neg(&a[2], &a[2])
+		t := e.t
+		neg(&t[0][0], &c[1][1])
+		c[1][1].set(&c[1][0])
+		c[1][0].set(&t[0][0])
+		fp2.neg(&c[2], &c[2])
 	default:
 		fp2.mul(&c[1], &c[1], &frobeniusCoeffs61[power%6])
 		fp2.mul(&c[2], &c[2], &frobeniusCoeffs62[power%6])
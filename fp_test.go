@@ -284,6 +284,156 @@ func TestFpSquareRoot(t *testing.T) {
 		}
 	}
 }
+func (w *wfe) big() *big.Int {
+	r := new(big.Int)
+	for i := 11; i >= 0; i-- {
+		r.Lsh(r, 64)
+		r.Add(r, new(big.Int).SetUint64(w[i]))
+	}
+	return r
+}
+
+func (w *wfe) setBig(a *big.Int) *wfe {
+	words := new(big.Int).Set(a)
+	mask := new(big.Int).SetUint64(^uint64(0))
+	for i := 0; i < 12; i++ {
+		w[i] = new(big.Int).And(words, mask).Uint64()
+		words.Rsh(words, 64)
+	}
+	return w
+}
+
+func TestFpWideArithmeticCrossAgainstBigInt(t *testing.T) {
+	p := modulus.big()
+	// pR = p * 2^384
+	pR := new(big.Int).Lsh(p, 384)
+	rInv := new(big.Int).ModInverse(new(big.Int).Lsh(big.NewInt(1), 384), p)
+	randWide := func() (*wfe, *big.Int) {
+		a := randScalar(pR)
+		return new(wfe).setBig(a), a
+	}
+	edges := []*big.Int{
+		big.NewInt(0),
+		new(big.Int).Sub(pR, big.NewInt(1)),
+		new(big.Int).Sub(pR, new(big.Int).Lsh(big.NewInt(1), 384)),
+		new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 384), big.NewInt(1)),
+	}
+	for i := 0; i < fuz; i++ {
+		a, _ := newRand(rand.Reader)
+		b, _ := newRand(rand.Reader)
+		w, c := new(wfe), new(fe)
+		wmul(w, a, b)
+		if w.big().Cmp(new(big.Int).Mul(a.big(), b.big())) != 0 {
+			t.Fatal("bad wide multiplication")
+		}
+		montRed(c, w)
+		expected := new(fe)
+		mul(expected, a, b)
+		if !c.equal(expected) {
+			t.Fatal("bad montgomerry reduction")
+		}
+	}
+	for i := 0; i < fuz+len(edges); i++ {
+		w0, a := randWide()
+		w1, b := randWide()
+		if i < len(edges) {
+			w0.setBig(edges[i])
+			a = edges[i]
+		}
+		c := new(fe)
+		montRed(c, w0)
+		expected := new(big.Int).Mul(a, rInv)
+		expected.Mod(expected, p)
+		if c.big().Cmp(expected) != 0 {
+			t.Fatal("bad montgomerry reduction")
+		}
+		w2 := new(wfe)
+		wadd(w2, w0, w1)
+		expected = new(big.Int).Add(a, b)
+		expected.Mod(expected, pR)
+		if w2.big().Cmp(expected) != 0 {
+			t.Fatal("bad wide addition")
+		}
+		wsub(w2, w0, w1)
+		expected = new(big.Int).Sub(a, b)
+		expected.Mod(expected, pR)
+		if w2.big().Cmp(expected) != 0 {
+			t.Fatal("bad wide subtraction")
+		}
+		wsub(w2, w1, w0)
+		expected = new(big.Int).Sub(b, a)
+		expected.Mod(expected, pR)
+		if w2.big().Cmp(expected) != 0 {
+			t.Fatal("bad wide subtraction")
+		}
+	}
+}
+
+func TestFpBatchMultiplication(t *testing.T) {
+	pMinusOne := new(fe).set(&modulus)
+	pMinusOne[0]--
+	for i := 0; i < fuz; i++ {
+		n := 8*(i%4) + i%8
+		a, b, c := make([]fe, n), make([]fe, n), make([]fe, n)
+		for j := 0; j < n; j++ {
+			x, _ := newRand(rand.Reader)
+			y, _ := newRand(rand.Reader)
+			a[j].set(x)
+			b[j].set(y)
+		}
+		if n > 1 {
+			a[0].set(pMinusOne)
+			b[0].set(pMinusOne)
+			b[1].zero()
+		}
+		mulBatch(c, a, b)
+		for j := 0; j < n; j++ {
+			expected := new(fe)
+			mul(expected, &a[j], &b[j])
+			if !equal(expected, &c[j]) {
+				t.Fatalf("batch multiplication doesn't match at %d", j)
+			}
+		}
+		mulBatch(a, a, b)
+		for j := 0; j < n; j++ {
+			if !equal(&a[j], &c[j]) {
+				t.Fatalf("in place batch multiplication doesn't match at %d", j)
+			}
+		}
+	}
+	for _, n := range [][2]int{{8, 16}, {16, 8}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("short input is expected to panic")
+				}
+			}()
+			mulBatch(make([]fe, 16), make([]fe, n[0]), make([]fe, n[1]))
+		}()
+	}
+}
+
+func TestFpBatchInversion(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		n := 1 + i%20
+		a, b := make([]fe, n), make([]fe, n)
+		for j := 0; j < n; j++ {
+			x, _ := newRand(rand.Reader)
+			a[j].set(x)
+		}
+		a[i%n].zero()
+		copy(b, a)
+		inverseBatch(b)
+		for j := 0; j < n; j++ {
+			expected := new(fe)
+			inverse(expected, &a[j])
+			if !equal(expected, &b[j]) {
+				t.Fatalf("batch inversion doesn't match at %d", j)
+			}
+		}
+	}
+}
+
 func TestFp2Serialization(t *testing.T) {
 	field := newFp2()
 	for i := 0; i < fuz; i++ {
@@ -838,6 +988,59 @@ func TestFp12Exponentiation(t *testing.T) {
 	}
 }
 
+func TestFp12CyclotomicExponentiation(t *testing.T) {
+	field := newFp12(nil)
+	toCyclotomic := func(a *fe12) *fe12 {
+		// a^((p^6 - 1) * (p^2 + 1))
+		c, t := field.new(), field.new()
+		field.conjugate(c, a)
+		field.inverse(t, a)
+		field.mul(c, c, t)
+		field.frobeniusMap(t, c, 2)
+		field.mul(c, c, t)
+		return c
+	}
+	exponents := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2), big.NewInt(1 << 40), x}
+	for i := 0; i < fuz; i++ {
+		a, _ := field.rand(rand.Reader)
+		a = toCyclotomic(a)
+		u, v := field.new(), field.new()
+		field.cyclotomicSquare(u, a)
+		field.cyclotomicSquareCompressed(v, a)
+		field.cyclotomicSquare(u, u)
+		field.cyclotomicSquareCompressed(v, v)
+		vs := []fe12{*v}
+		if !field.cyclotomicDecompress(vs) || !field.equal(u, &vs[0]) {
+			t.Fatalf("bad compressed squaring")
+		}
+		for _, s := range append(exponents, randScalar(q)) {
+			field.exp(u, a, s)
+			if !field.cyclotomicExpCompressed(v, a, s) || !field.equal(u, v) {
+				t.Fatalf("bad compressed exponentiation")
+			}
+			field.cyclotomicExp(v, a, s)
+			if !field.equal(u, v) {
+				t.Fatalf("bad cyclotomic exponentiation")
+			}
+		}
+		// squares of one have g2 = g3 = 0 and fall back to uncompressed squaring
+		if field.cyclotomicExpCompressed(v, field.one(), x) {
+			t.Fatalf("compressed squares of one are not expected to be decompressed")
+		}
+		field.cyclotomicExp(v, field.one(), x)
+		if !field.equal(v, field.one()) {
+			t.Fatalf("1^x == 1")
+		}
+		// g2 = g3 = 0 doesn't determine g4
+		field.copy(v, a)
+		v[0][2], v[1][0] = fe2{}, fe2{}
+		vs = []fe12{*v}
+		if field.cyclotomicDecompress(vs) || !field.equal(v, &vs[0]) {
+			t.Fatalf("degenerate element is expected to be rejected without modification")
+		}
+	}
+}
+
 func TestFp12Inversion(t *testing.T) {
 	field := newFp12(nil)
 	for i := 0; i < fuz; i++ {
@@ -868,6 +1071,41 @@ func TestFp12Inversion(t *testing.T) {
 	}
 }
 
+func TestFp12FrobeniusMap(t *testing.T) {
+	field := newFp12(nil)
+	for i := 0; i < fuz; i++ {
+		a, _ := field.rand(rand.Reader)
+		a0, u, v := field.new(), field.new(), field.new()
+		field.copy(a0, a)
+		field.copy(v, a)
+		for power := uint(0); power < 12; power++ {
+			field.frobeniusMap(u, a, power)
+			if !field.equal(a, a0) {
+				t.Fatalf("input is modified, power %d", power)
+			}
+			if !field.equal(u, v) {
+				t.Fatalf("bad frobenius map, power %d", power)
+			}
+			field.copy(u, a)
+			field.frobeniusMapAssign(u, power)
+			if !field.equal(u, v) {
+				t.Fatalf("bad frobenius map assign, power %d", power)
+			}
+			field.frobeniusMapAssign(v, 1)
+		}
+		if !field.equal(v, a) {
+			t.Fatalf("a^(p^12) == a")
+		}
+	}
+	a, _ := field.rand(rand.Reader)
+	u, v := field.new(), field.new()
+	field.frobeniusMap(u, a, 1)
+	field.exp(v, a, modulus.big())
+	if !field.equal(u, v) {
+		t.Fatalf("a^p == frobenius(a)")
+	}
+}
+
 func BenchmarkMultiplication(t *testing.B) {
 	a, _ := newRand(rand.Reader)
 	b, _ := newRand(rand.Reader)
@@ -878,6 +1116,54 @@ func BenchmarkMultiplication(t *testing.B) {
 	}
 }
 
+func BenchmarkBatchMultiplication(t *testing.B) {
+	n := 64
+	a, b, c := make([]fe, n), make([]fe, n), make([]fe, n)
+	for j := 0; j < n; j++ {
+		x, _ := newRand(rand.Reader)
+		y, _ := newRand(rand.Reader)
+		a[j].set(x)
+		b[j].set(y)
+	}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		mulBatch(c, a, b)
+	}
+}
+
+func BenchmarkFp2Multiplication(t *testing.B) {
+	field := newFp2()
+	a, _ := field.rand(rand.Reader)
+	b, _ := field.rand(rand.Reader)
+	c := field.new()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		field.mul(c, a, b)
+	}
+}
+
+func BenchmarkFp6Multiplication(t *testing.B) {
+	field := newFp6(nil)
+	a, _ := field.rand(rand.Reader)
+	b, _ := field.rand(rand.Reader)
+	c := field.new()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		field.mul(c, a, b)
+	}
+}
+
+func BenchmarkFp12Multiplication(t *testing.B) {
+	field := newFp12(nil)
+	a, _ := field.rand(rand.Reader)
+	b, _ := field.rand(rand.Reader)
+	c := field.new()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		field.mul(c, a, b)
+	}
+}
+
 func padBytes(in []byte, size int) []byte {
 	out := make([]byte, size)
 	if len(in) > size {
@@ -2,8 +2,11 @@ package bls12381
 
 import (
 	"fmt"
+	"hash"
 	"math"
 	"math/big"
+	"runtime"
+	"sync"
 )
 
 // PointG1 is type for point in G1.
@@ -279,10 +282,25 @@ func (g *G1) Equal(p1, p2 *PointG1) bool {
 }
 
 // InCorrectSubgroup checks whether given point is in correct subgroup.
+// It checks φ(P) == -x^2 * P instead of multiplying by the group order.
+// https://eprint.iacr.org/2021/1130
 func (g *G1) InCorrectSubgroup(p *PointG1) bool {
-	tmp := &PointG1{}
-	g.MulScalar(tmp, p, q)
-	return g.IsZero(tmp)
+	if g.IsZero(p) {
+		return true
+	}
+	t0, t1 := &PointG1{}, &PointG1{}
+	g.MulScalar(t0, p, x)
+	g.MulScalar(t0, t0, x)
+	g.Neg(t0, t0)
+	g.glvEndomorphism(t1, p)
+	return g.Equal(t0, t1)
+}
+
+// glvEndomorphism sets c = φ(p) = (βx, y).
+func (g *G1) glvEndomorphism(c, p *PointG1) {
+	mul(&c[0], &p[0], glvBeta)
+	c[1].set(&p[1])
+	c[2].set(&p[2])
 }
 
 // IsOnCurve checks a G1 point is on curve.
@@ -307,6 +325,38 @@ func (g *G1) IsAffine(p *PointG1) bool {
 	return equal(&p[2], one())
 }
 
+// AffineBatch converts given points to affine form in place.
+// It shares a single field inversion among all points and multiplies coordinates in batches.
+func (g *G1) AffineBatch(p []*PointG1) {
+	idx := make([]int, 0, len(p))
+	for i := range p {
+		if !g.IsZero(p[i]) && !g.IsAffine(p[i]) {
+			idx = append(idx, i)
+		}
+	}
+	n := len(idx)
+	if n == 0 {
+		return
+	}
+	buf := make([]fe, 4*n)
+	x, y, z, zz := buf[:n], buf[n:2*n], buf[2*n:3*n], buf[3*n:]
+	for i, j := range idx {
+		x[i].set(&p[j][0])
+		y[i].set(&p[j][1])
+		z[i].set(&p[j][2])
+	}
+	inverseBatch(z)
+	mulBatch(zz, z, z)
+	mulBatch(x, x, zz)
+	mulBatch(z, z, zz)
+	mulBatch(y, y, z)
+	for i, j := range idx {
+		p[j][0].set(&x[i])
+		p[j][1].set(&y[i])
+		p[j][2].set(one())
+	}
+}
+
 // Add adds two G1 points p1, p2 and assigns the result to point at first argument.
 func (g *G1) Affine(p *PointG1) *PointG1 {
 	if g.IsZero(p) {
@@ -443,6 +493,135 @@ func (g *G1) ClearCofactor(p *PointG1) {
 	g.MulScalar(p, p, cofactorEFFG1)
 }
 
+// Constant time operations below are used in hashing to curve and they take points in
+// homogeneous projective coordinates (X : Y : Z) where x = X / Z, point at infinity is (0 : 1 : 0).
+// Complete formulas of the curve are valid for any input since the curve has no point of order two.
+// https://eprint.iacr.org/2015/1060
+
+// mulByB3 sets c = 3 * b * a where b = 4.
+func (g *G1) mulByB3(c, a *fe) {
+	t := g.t[8]
+	double(t, a)
+	add(t, t, a)
+	double(c, t)
+	double(c, c)
+}
+
+// addCT sets r = p1 + p2 with complete formula, Algorithm 7 in https://eprint.iacr.org/2015/1060
+func (g *G1) addCT(r, p1, p2 *PointG1) *PointG1 {
+	t := g.t
+	t0, t1, t2, t3, t4 := t[0], t[1], t[2], t[3], t[4]
+	x3, y3, z3 := t[5], t[6], t[7]
+	mul(t0, &p1[0], &p2[0])
+	mul(t1, &p1[1], &p2[1])
+	mul(t2, &p1[2], &p2[2])
+	add(t3, &p1[0], &p1[1])
+	add(t4, &p2[0], &p2[1])
+	mul(t3, t3, t4)
+	add(t4, t0, t1)
+	sub(t3, t3, t4)
+	add(t4, &p1[1], &p1[2])
+	add(x3, &p2[1], &p2[2])
+	mul(t4, t4, x3)
+	add(x3, t1, t2)
+	sub(t4, t4, x3)
+	add(x3, &p1[0], &p1[2])
+	add(y3, &p2[0], &p2[2])
+	mul(x3, x3, y3)
+	add(y3, t0, t2)
+	sub(y3, x3, y3)
+	double(x3, t0)
+	add(t0, x3, t0)
+	g.mulByB3(t2, t2)
+	add(z3, t1, t2)
+	sub(t1, t1, t2)
+	g.mulByB3(y3, y3)
+	mul(x3, t4, y3)
+	mul(t2, t3, t1)
+	sub(x3, t2, x3)
+	mul(y3, y3, t0)
+	mul(t1, t1, z3)
+	add(y3, t1, y3)
+	mul(t0, t0, t3)
+	mul(z3, z3, t4)
+	add(z3, z3, t0)
+	r[0].set(x3)
+	r[1].set(y3)
+	r[2].set(z3)
+	return r
+}
+
+// doubleCT sets r = 2 * p with complete formula, Algorithm 9 in https://eprint.iacr.org/2015/1060
+func (g *G1) doubleCT(r, p *PointG1) *PointG1 {
+	t := g.t
+	t0, t1, t2 := t[0], t[1], t[2]
+	x3, y3, z3 := t[5], t[6], t[7]
+	square(t0, &p[1])
+	double(z3, t0)
+	double(z3, z3)
+	double(z3, z3)
+	mul(t1, &p[1], &p[2])
+	square(t2, &p[2])
+	g.mulByB3(t2, t2)
+	mul(x3, t2, z3)
+	add(y3, t0, t2)
+	mul(z3, t1, z3)
+	double(t1, t2)
+	add(t2, t1, t2)
+	sub(t0, t0, t2)
+	mul(y3, t0, y3)
+	add(y3, x3, y3)
+	mul(t1, &p[0], &p[1])
+	mul(x3, t0, t1)
+	double(x3, x3)
+	r[0].set(x3)
+	r[1].set(y3)
+	r[2].set(z3)
+	return r
+}
+
+// mulScalarCT sets c = e * p with complete formulas. Scalar is expected to be public,
+// running time depends only on its bits.
+func (g *G1) mulScalarCT(c, p *PointG1, e *big.Int) *PointG1 {
+	r, n := &PointG1{*zero(), *one(), *zero()}, new(PointG1).Set(p)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		g.doubleCT(r, r)
+		if e.Bit(i) == 1 {
+			g.addCT(r, r, n)
+		}
+	}
+	return c.Set(r)
+}
+
+// clearCofactorCT maps given point to correct subgroup as ClearCofactor does.
+func (g *G1) clearCofactorCT(p *PointG1) {
+	g.mulScalarCT(p, p, cofactorEFFG1)
+}
+
+// affineCT converts a point to affine form with Fermat inversion.
+// Result is in the same form that Affine returns.
+func (g *G1) affineCT(p *PointG1) *PointG1 {
+	t := g.t
+	isInfinity := isZeroCT(&p[2])
+	inverseCT(t[0], &p[2])
+	mul(&p[0], &p[0], t[0])
+	mul(t[1], &p[1], t[0])
+	cmov(&p[1], t[1], one(), isInfinity)
+	cmov(&p[2], one(), zero(), isInfinity)
+	return p
+}
+
+// projectiveToJacobian converts a point in homogeneous projective coordinates to Jacobian coordinates.
+// Jacobian form of (X : Y : Z) is (X * Z : Y * Z^2 : Z).
+func (g *G1) projectiveToJacobian(p *PointG1) *PointG1 {
+	t := g.t
+	mul(&p[0], &p[0], &p[2])
+	square(t[0], &p[2])
+	mul(t[0], &p[1], t[0])
+	cmov(&p[1], t[0], one(), isZeroCT(&p[2]))
+	return p
+}
+
 // MultiExp calculates multi exponentiation. Given pairs of G1 point and scalar values
 // (P_0, e_0), (P_1, e_1), ... (P_n, e_n) calculates r = e_0 * P_0 + e_1 * P_1 + ... + e_n * P_n
 // Length of points and scalars are expected to be equal, otherwise an error is returned.
@@ -503,56 +682,245 @@ func (g *G1) MultiExp(r *PointG1, points []*PointG1, powers []*big.Int) (*PointG
 
 // MapToCurve given a byte slice returns a valid G1 point.
 // This mapping function implements the Simplified Shallue-van de Woestijne-Ulas method.
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-6.6.2
 // Input byte slice should be a valid field element, otherwise an error is returned.
 func (g *G1) MapToCurve(in []byte) (*PointG1, error) {
 	u, err := fromBytes(in)
 	if err != nil {
 		return nil, err
 	}
-	x, y := swuMapG1(u)
-	isogenyMapG1(x, y)
-	one := one()
-	p := &PointG1{*x, *y, *one}
-	g.ClearCofactor(p)
-	return g.Affine(p), nil
+	x, y := swuMapG1CT(u)
+	p := g.New()
+	isogenyMapG1Projective(p, x, y)
+	g.clearCofactorCT(p)
+	return g.affineCT(p), nil
 }
 
 // EncodeToCurve given a message and domain seperator tag returns the hash result
 // which is a valid curve point.
 // Implementation follows BLS12381G1_XMD:SHA-256_SSWU_NU_ suite at
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-8.8.1
+// Field and point operations on the message hash don't branch on it.
 func (g *G1) EncodeToCurve(msg, domain []byte) (*PointG1, error) {
-	hashRes, err := hashToFpXMDSHA256(msg, domain, 1)
-	if err != nil {
-		return nil, err
-	}
-	u := hashRes[0]
-	x, y := swuMapG1(u)
-	isogenyMapG1(x, y)
-	one := one()
-	p := &PointG1{*x, *y, *one}
-	g.ClearCofactor(p)
-	return g.Affine(p), nil
+	return g.encodeToCurve(expandMsgSHA256XMD, msg, domain)
 }
 
 // HashToCurve given a message and domain seperator tag returns the hash result
 // which is a valid curve point.
 // Implementation follows BLS12381G1_XMD:SHA-256_SSWU_RO_ suite at
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-8.8.1
+// Field and point operations on the message hash don't branch on it.
 func (g *G1) HashToCurve(msg, domain []byte) (*PointG1, error) {
-	hashRes, err := hashToFpXMDSHA256(msg, domain, 2)
+	return g.hashToCurve(expandMsgSHA256XMD, msg, domain)
+}
+
+// EncodeToCurveWith is the same as EncodeToCurve but it uses expand_message_xmd with given hash function.
+// Output size of the hash function should be at least 32 bytes.
+func (g *G1) EncodeToCurveWith(hashFn func() hash.Hash, msg, domain []byte) (*PointG1, error) {
+	return g.encodeToCurve(ExpandMsgXMD(hashFn), msg, domain)
+}
+
+// HashToCurveWith is the same as HashToCurve but it uses expand_message_xmd with given hash function
+// such as SHA-512 for a BLS12381G1_XMD:SHA-512_SSWU_RO_ suite.
+// Output size of the hash function should be at least 32 bytes.
+func (g *G1) HashToCurveWith(hashFn func() hash.Hash, msg, domain []byte) (*PointG1, error) {
+	return g.hashToCurve(ExpandMsgXMD(hashFn), msg, domain)
+}
+
+// EncodeToCurveSHAKE128 is the same as EncodeToCurve but it uses
+// expand_message_xof with SHAKE128 that is BLS12381G1_XOF:SHAKE-128_SSWU_NU_ suite.
+func (g *G1) EncodeToCurveSHAKE128(msg, domain []byte) (*PointG1, error) {
+	return g.encodeToCurve(expandMsgSHAKE128XOF, msg, domain)
+}
+
+// HashToCurveSHAKE128 is the same as HashToCurve but it uses
+// expand_message_xof with SHAKE128 that is BLS12381G1_XOF:SHAKE-128_SSWU_RO_ suite.
+func (g *G1) HashToCurveSHAKE128(msg, domain []byte) (*PointG1, error) {
+	return g.hashToCurve(expandMsgSHAKE128XOF, msg, domain)
+}
+
+// EncodeToCurveSHAKE256 is the same as EncodeToCurve but it uses
+// expand_message_xof with SHAKE256 that is BLS12381G1_XOF:SHAKE-256_SSWU_NU_ suite.
+func (g *G1) EncodeToCurveSHAKE256(msg, domain []byte) (*PointG1, error) {
+	return g.encodeToCurve(expandMsgSHAKE256XOF, msg, domain)
+}
+
+// HashToCurveSHAKE256 is the same as HashToCurve but it uses
+// expand_message_xof with SHAKE256 that is BLS12381G1_XOF:SHAKE-256_SSWU_RO_ suite.
+func (g *G1) HashToCurveSHAKE256(msg, domain []byte) (*PointG1, error) {
+	return g.hashToCurve(expandMsgSHAKE256XOF, msg, domain)
+}
+
+// HashToCurveWithSuite hashes given message to a curve point as the suite defines,
+// running hash_to_curve for RO and encode_to_curve for NU suites.
+// Nil suites, suites that are not created with NewSuite and suites of the other group are rejected.
+func (g *G1) HashToCurveWithSuite(s *Suite, msg, domain []byte) (*PointG1, error) {
+	if err := s.check(1); err != nil {
+		return nil, err
+	}
+	if s.RandomOracle {
+		return g.hashToCurve(s.expand, msg, domain)
+	}
+	return g.encodeToCurve(s.expand, msg, domain)
+}
+
+// G1Hasher hashes a message that is written in pieces to a G1 point.
+// Message is not buffered since it is needed only once while it is expanded.
+// A hasher is not safe for concurrent use.
+type G1Hasher struct {
+	g            *G1
+	stream       messageStream
+	randomOracle bool
+	done         bool
+}
+
+// NewHasher returns a hasher for given suite and domain separation tag.
+// If suite is nil SuiteG1XMDSHA256SSWURO suite is used which is the suite of HashToCurve.
+func (g *G1) NewHasher(s *Suite, domain []byte) (*G1Hasher, error) {
+	if s == nil {
+		s, _ = NewSuite(SuiteG1XMDSHA256SSWURO)
+	}
+	if err := s.check(1); err != nil {
+		return nil, err
+	}
+	stream, err := s.newStream(domain)
+	if err != nil {
+		return nil, err
+	}
+	return &G1Hasher{g: g, stream: stream, randomOracle: s.RandomOracle}, nil
+}
+
+// Write adds more data to the message. It fails if Sum is already called.
+func (h *G1Hasher) Write(msg []byte) (int, error) {
+	if h.done {
+		return 0, fmt.Errorf("hasher is finalized")
+	}
+	return h.stream.Write(msg)
+}
+
+// Sum returns the hash of the message written so far.
+// Unlike hash.Hash the hasher is finalized and it should be reset before it is used again.
+func (h *G1Hasher) Sum() (*PointG1, error) {
+	if h.done {
+		return nil, fmt.Errorf("hasher is finalized")
+	}
+	h.done = true
+	// message and domain are already in the stream
+	expand := func(_, _ []byte, outLen int) ([]byte, error) {
+		return h.stream.expand(outLen)
+	}
+	if h.randomOracle {
+		return h.g.hashToCurve(expand, nil, nil)
+	}
+	return h.g.encodeToCurve(expand, nil, nil)
+}
+
+// Reset discards the message written so far and keeps the suite and the domain separation tag.
+func (h *G1Hasher) Reset() {
+	h.stream.reset()
+	h.done = false
+}
+
+func (g *G1) encodeToCurve(expand Expander, msg, domain []byte) (*PointG1, error) {
+	hashRes, err := hashToFp(expand, msg, domain, 1)
+	if err != nil {
+		return nil, err
+	}
+	u := hashRes[0]
+	x, y := swuMapG1CT(u)
+	p := g.New()
+	isogenyMapG1Projective(p, x, y)
+	g.clearCofactorCT(p)
+	return g.affineCT(p), nil
+}
+
+func (g *G1) hashToCurve(expand Expander, msg, domain []byte) (*PointG1, error) {
+	hashRes, err := hashToFp(expand, msg, domain, 2)
 	if err != nil {
 		return nil, err
 	}
 	u0, u1 := hashRes[0], hashRes[1]
-	x0, y0 := swuMapG1(u0)
-	x1, y1 := swuMapG1(u1)
-	one := one()
-	p0, p1 := &PointG1{*x0, *y0, *one}, &PointG1{*x1, *y1, *one}
-	g.Add(p0, p0, p1)
-	g.Affine(p0)
-	isogenyMapG1(&p0[0], &p0[1])
-	g.ClearCofactor(p0)
-	return g.Affine(p0), nil
+	x0, y0 := swuMapG1CT(u0)
+	x1, y1 := swuMapG1CT(u1)
+	p0, p1 := g.New(), g.New()
+	isogenyMapG1Projective(p0, x0, y0)
+	isogenyMapG1Projective(p1, x1, y1)
+	g.addCT(p0, p0, p1)
+	g.clearCofactorCT(p0)
+	return g.affineCT(p0), nil
+}
+
+// HashToCurveBatch hashes each message as HashToCurve does with the same domain separation tag.
+// Field inversions of the map and the affine conversions are shared among messages
+// with Montgomery's trick and messages are split across GOMAXPROCS goroutines.
+// Unlike HashToCurve, shared inversions are not constant time.
+func (g *G1) HashToCurveBatch(msgs [][]byte, domain []byte) ([]*PointG1, error) {
+	out := make([]*PointG1, len(msgs))
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(msgs) {
+		workers = len(msgs)
+	}
+	if workers < 2 {
+		if err := g.hashToCurveBatch(out, expandMsgSHA256XMD, msgs, domain); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	// each worker has its own temporaries and the caller takes the first part
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 1; i < workers; i++ {
+		lo, hi := i*len(msgs)/workers, (i+1)*len(msgs)/workers
+		wg.Add(1)
+		go func(i, lo, hi int) {
+			defer wg.Done()
+			errs[i] = NewG1().hashToCurveBatch(out[lo:hi], expandMsgSHA256XMD, msgs[lo:hi], domain)
+		}(i, lo, hi)
+	}
+	hi := len(msgs) / workers
+	errs[0] = g.hashToCurveBatch(out[:hi], expandMsgSHA256XMD, msgs[:hi], domain)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// hashToCurveBatch is the serial part of HashToCurveBatch that writes points to out.
+func (g *G1) hashToCurveBatch(out []*PointG1, expand Expander, msgs [][]byte, domain []byte) error {
+	n := len(msgs)
+	if n == 0 {
+		return nil
+	}
+	// map u0 and u1 of all messages and invert x denominators together
+	x, den, y := make([]fe, 2*n), make([]fe, 2*n), make([]fe, 2*n)
+	for i := range msgs {
+		u, err := hashToFp(expand, msgs[i], domain, 2)
+		if err != nil {
+			return err
+		}
+		for j := 0; j < 2; j++ {
+			xNum, xDen, yj := swuMapG1Fraction(u[j])
+			x[2*i+j].set(xNum)
+			den[2*i+j].set(xDen)
+			y[2*i+j].set(yj)
+		}
+	}
+	inverseBatch(den)
+	mulBatch(x, x, den)
+	// isogeny is applied in projective form, so that points in its kernel are mapped to infinity
+	// and the sum is taken on the curve of G1
+	points := make([]*PointG1, n)
+	for i := range points {
+		p0, p1 := g.New(), g.New()
+		isogenyMapG1Projective(p0, &x[2*i], &y[2*i])
+		isogenyMapG1Projective(p1, &x[2*i+1], &y[2*i+1])
+		points[i] = g.Add(p0, g.projectiveToJacobian(p0), g.projectiveToJacobian(p1))
+		g.ClearCofactor(points[i])
+	}
+	g.AffineBatch(points)
+	copy(out, points)
+	return nil
 }
@@ -3,8 +3,13 @@ package bls12381
 import (
 	"bytes"
 	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
 	"io/ioutil"
 	"math/big"
+	mrand "math/rand"
+	"strings"
 	"testing"
 )
 
@@ -31,6 +36,20 @@ func (g *G1) randAffine() *PointG1 {
 	return g.Affine(g.rand())
 }
 
+func (g *G1) randCurvePoint() *PointG1 {
+	// point on curve which is not in correct subgroup with overwhelming probability
+	for {
+		x, _ := newRand(rand.Reader)
+		y := new(fe)
+		square(y, x)
+		mul(y, y, x)
+		add(y, y, b)
+		if sqrt(y, y) {
+			return &PointG1{*x, *y, *one()}
+		}
+	}
+}
+
 func TestG1Serialization(t *testing.T) {
 	var err error
 	g1 := NewG1()
@@ -290,6 +309,64 @@ func TestG1MultiExpBatch(t *testing.T) {
 	}
 }
 
+func TestG1SubgroupCheck(t *testing.T) {
+	g := NewG1()
+	if !g.InCorrectSubgroup(g.Zero()) || !g.InCorrectSubgroup(g.One()) {
+		t.Fatalf("generator and point at infinity are expected to be in correct subgroup")
+	}
+	for i := 0; i < fuz; i++ {
+		if !g.InCorrectSubgroup(g.rand()) {
+			t.Fatalf("point is expected to be in correct subgroup")
+		}
+		p := g.randCurvePoint()
+		if !g.IsOnCurve(p) {
+			t.Fatalf("point is expected to be on curve")
+		}
+		// cross check against multiplication by group order
+		r := g.New()
+		g.MulScalar(r, p, q)
+		if g.InCorrectSubgroup(p) != g.IsZero(r) {
+			t.Fatalf("fast subgroup check doesn't match")
+		}
+		// cofactor cleared point is in correct subgroup
+		g.ClearCofactor(p)
+		if !g.InCorrectSubgroup(p) {
+			t.Fatalf("point is expected to be in correct subgroup after clearing cofactor")
+		}
+	}
+}
+
+func TestG1AffineBatch(t *testing.T) {
+	g := NewG1()
+	n := fuz + 3
+	points := make([]*PointG1, n)
+	for i := 0; i < n; i++ {
+		points[i] = g.rand()
+	}
+	points[0] = g.Zero()
+	points[1] = g.randAffine()
+	copies := make([]*PointG1, n)
+	for i := 0; i < n; i++ {
+		copies[i] = g.New().Set(points[i])
+	}
+	g.AffineBatch(points)
+	for j := 0; j < n; j++ {
+		expected := g.Affine(copies[j])
+		if g.IsZero(expected) {
+			if !g.IsZero(points[j]) {
+				t.Fatalf("point at infinity is expected")
+			}
+			continue
+		}
+		if !g.IsAffine(points[j]) {
+			t.Fatalf("point is not in affine form")
+		}
+		if !(equal(&points[j][0], &expected[0]) && equal(&points[j][1], &expected[1])) {
+			t.Fatalf("batch affine conversion doesn't match")
+		}
+	}
+}
+
 func TestG1EncodeToCurve(t *testing.T) {
 	domain := []byte("BLS12381G1_XMD:SHA-256_SSWU_NU_TESTGEN")
 	for i, v := range []struct {
@@ -382,6 +459,562 @@ func TestG1HashToCurve(t *testing.T) {
 	}
 }
 
+func TestG1HashToCurveRFC9380(t *testing.T) {
+	// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-J.9
+	for _, suite := range []struct {
+		suite   string
+		hash    func(g *G1, msg, domain []byte) (*PointG1, error)
+		vectors []struct {
+			msg      []byte
+			expected []byte
+		}
+	}{
+		{
+			suite: SuiteG1XMDSHA256SSWURO,
+			hash:  (*G1).HashToCurve,
+			vectors: []struct {
+				msg      []byte
+				expected []byte
+			}{
+				{
+					msg: []byte(""),
+					expected: fromHex(-1,
+						"052926add2207b76ca4fa57a8734416c8dc95e24501772c814278700eed6d1e4e8cf62d9c09db0fac349612b759e79a1",
+						"08ba738453bfed09cb546dbb0783dbb3a5f1f566ed67bb6be0e8c67e2e81a4cc68ee29813bb7994998f3eae0c9c6a265",
+					),
+				},
+				{
+					msg: []byte("abc"),
+					expected: fromHex(-1,
+						"03567bc5ef9c690c2ab2ecdf6a96ef1c139cc0b2f284dca0a9a7943388a49a3aee664ba5379a7655d3c68900be2f6903",
+						"0b9c15f3fe6e5cf4211f346271d7b01c8f3b28be689c8429c85b67af215533311f0b8dfaaa154fa6b88176c229f2885d",
+					),
+				},
+				{
+					msg: []byte("abcdef0123456789"),
+					expected: fromHex(-1,
+						"11e0b079dea29a68f0383ee94fed1b940995272407e3bb916bbf268c263ddd57a6a27200a784cbc248e84f357ce82d98",
+						"03a87ae2caf14e8ee52e51fa2ed8eefe80f02457004ba4d486d6aa1f517c0889501dc7413753f9599b099ebcbbd2d709",
+					),
+				},
+				{
+					msg: []byte("q128_" + strings.Repeat("q", 128)),
+					expected: fromHex(-1,
+						"15f68eaa693b95ccb85215dc65fa81038d69629f70aeee0d0f677cf22285e7bf58d7cb86eefe8f2e9bc3f8cb84fac488",
+						"1807a1d50c29f430b8cafc4f8638dfeeadf51211e1602a5f184443076715f91bb90a48ba1e370edce6ae1062f5e6dd38",
+					),
+				},
+				{
+					msg: []byte("a512_" + strings.Repeat("a", 512)),
+					expected: fromHex(-1,
+						"082aabae8b7dedb0e78aeb619ad3bfd9277a2f77ba7fad20ef6aabdc6c31d19ba5a6d12283553294c1825c4b3ca2dcfe",
+						"05b84ae5a942248eea39e1d91030458c40153f3b654ab7872d779ad1e942856a20c438e8d99bc8abfbf74729ce1f7ac8",
+					),
+				},
+			},
+		},
+		{
+			suite: SuiteG1XMDSHA256SSWUNU,
+			hash:  (*G1).EncodeToCurve,
+			vectors: []struct {
+				msg      []byte
+				expected []byte
+			}{
+				{
+					msg: []byte(""),
+					expected: fromHex(-1,
+						"184bb665c37ff561a89ec2122dd343f20e0f4cbcaec84e3c3052ea81d1834e192c426074b02ed3dca4e7676ce4ce48ba",
+						"04407b8d35af4dacc809927071fc0405218f1401a6d15af775810e4e460064bcc9468beeba82fdc751be70476c888bf3",
+					),
+				},
+				{
+					msg: []byte("abc"),
+					expected: fromHex(-1,
+						"009769f3ab59bfd551d53a5f846b9984c59b97d6842b20a2c565baa167945e3d026a3755b6345df8ec7e6acb6868ae6d",
+						"1532c00cf61aa3d0ce3e5aa20c3b531a2abd2c770a790a2613818303c6b830ffc0ecf6c357af3317b9575c567f11cd2c",
+					),
+				},
+				{
+					msg: []byte("abcdef0123456789"),
+					expected: fromHex(-1,
+						"1974dbb8e6b5d20b84df7e625e2fbfecb2cdb5f77d5eae5fb2955e5ce7313cae8364bc2fff520a6c25619739c6bdcb6a",
+						"15f9897e11c6441eaa676de141c8d83c37aab8667173cbe1dfd6de74d11861b961dccebcd9d289ac633455dfcc7013a3",
+					),
+				},
+				{
+					msg: []byte("q128_" + strings.Repeat("q", 128)),
+					expected: fromHex(-1,
+						"0a7a047c4a8397b3446450642c2ac64d7239b61872c9ae7a59707a8f4f950f101e766afe58223b3bff3a19a7f754027c",
+						"1383aebba1e4327ccff7cf9912bda0dbc77de048b71ef8c8a81111d71dc33c5e3aa6edee9cf6f5fe525d50cc50b77cc9",
+					),
+				},
+				{
+					msg: []byte("a512_" + strings.Repeat("a", 512)),
+					expected: fromHex(-1,
+						"0e7a16a975904f131682edbb03d9560d3e48214c9986bd50417a77108d13dc957500edf96462a3d01e62dc6cd468ef11",
+						"0ae89e677711d05c30a48d6d75e76ca9fb70fe06c6dd6ff988683d89ccde29ac7d46c53bb97a59b1901abf1db66052db",
+					),
+				},
+			},
+		},
+	} {
+		domain := []byte("QUUX-V01-CS02-with-" + suite.suite)
+		g := NewG1()
+		for i, v := range suite.vectors {
+			p0, err := suite.hash(g, v.msg, domain)
+			if err != nil {
+				t.Fatal("hash to point fails", suite.suite, i, err)
+			}
+			if !bytes.Equal(g.ToBytes(p0), v.expected) {
+				t.Fatal("hash to point fails", suite.suite, i)
+			}
+		}
+	}
+}
+
+func TestG1HashToCurveXOF(t *testing.T) {
+	g := NewG1()
+	msg := []byte("abc")
+	for i, v := range []struct {
+		expand Expander
+		hash   func(msg, domain []byte) (*PointG1, error)
+		encode func(msg, domain []byte) (*PointG1, error)
+		suites [2]string
+	}{
+		{expandMsgSHAKE128XOF, g.HashToCurveSHAKE128, g.EncodeToCurveSHAKE128, [2]string{SuiteG1XOFSHAKE128SSWURO, SuiteG1XOFSHAKE128SSWUNU}},
+		{expandMsgSHAKE256XOF, g.HashToCurveSHAKE256, g.EncodeToCurveSHAKE256, [2]string{SuiteG1XOFSHAKE256SSWURO, SuiteG1XOFSHAKE256SSWUNU}},
+	} {
+		// hash_to_curve(msg) = map_to_curve(u0) + map_to_curve(u1)
+		// since both isogeny map and cofactor clearing are homomorphisms
+		domain := []byte("QUUX-V01-CS02-with-" + v.suites[0])
+		p, err := v.hash(msg, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := hashToFp(v.expand, msg, domain, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q0, _ := g.MapToCurve(toBytes(u[0]))
+		q1, _ := g.MapToCurve(toBytes(u[1]))
+		g.Add(q0, q0, q1)
+		if !g.Equal(p, q0) || !g.IsOnCurve(p) || !g.InCorrectSubgroup(p) {
+			t.Fatal("bad hash to curve", i)
+		}
+		domain = []byte("QUUX-V01-CS02-with-" + v.suites[1])
+		p, err = v.encode(msg, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, _ = hashToFp(v.expand, msg, domain, 1)
+		q0, _ = g.MapToCurve(toBytes(u[0]))
+		if !g.Equal(p, q0) {
+			t.Fatal("bad encode to curve", i)
+		}
+		// must differ from xmd suite with the same tag
+		q0, _ = g.EncodeToCurve(msg, domain)
+		if g.Equal(p, q0) {
+			t.Fatal("xof and xmd suites are expected to differ", i)
+		}
+	}
+}
+
+func TestG1HashToCurveWith(t *testing.T) {
+	g := NewG1()
+	msg, domain := []byte("abc"), []byte("QUUX-V01-CS02-with-"+SuiteG1XMDSHA256SSWURO)
+	p0, err := g.HashToCurveWith(sha256.New, msg, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p1, _ := g.HashToCurve(msg, domain)
+	if !g.Equal(p0, p1) {
+		t.Fatal("bad hash to curve with sha256")
+	}
+	p0, err = g.EncodeToCurveWith(sha256.New, msg, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p1, _ = g.EncodeToCurve(msg, domain)
+	if !g.Equal(p0, p1) {
+		t.Fatal("bad encode to curve with sha256")
+	}
+	p0, err = g.HashToCurveWith(sha512.New, msg, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.IsOnCurve(p0) || !g.InCorrectSubgroup(p0) {
+		t.Fatal("bad hash to curve with sha512")
+	}
+	p1, _ = g.HashToCurve(msg, domain)
+	if g.Equal(p0, p1) {
+		t.Fatal("hash functions are expected to give different points")
+	}
+	if _, err := g.HashToCurveWith(sha1.New, msg, domain); err == nil {
+		t.Fatal("short hash output is expected to fail")
+	}
+}
+
+func TestG1Hasher(t *testing.T) {
+	g := NewG1()
+	msg := make([]byte, 10000)
+	_, _ = rand.Read(msg)
+	// long tag is hashed before the message is written
+	longDomain := []byte(strings.Repeat("a", 300))
+	for _, id := range []string{SuiteG1XMDSHA256SSWURO, SuiteG1XMDSHA256SSWUNU, SuiteG1XOFSHAKE128SSWURO, SuiteG1XOFSHAKE256SSWUNU, "BLS12381G1_XMD:SHA-512_SSWU_RO_"} {
+		s, err := NewSuite(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, domain := range [][]byte{s.DST("QUUX", 1, 2), longDomain} {
+			expected, err := g.HashToCurveWithSuite(s, msg, domain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h, err := g.NewHasher(s, domain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for j := 0; j < 2; j++ {
+				for rest := msg; len(rest) > 0; {
+					n := mrand.Intn(1000)
+					if n > len(rest) {
+						n = len(rest)
+					}
+					if _, err := h.Write(rest[:n]); err != nil {
+						t.Fatal(err)
+					}
+					rest = rest[n:]
+				}
+				p, err := h.Sum()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !g.Equal(p, expected) {
+					t.Fatalf("bad streaming hash with suite %s", id)
+				}
+				if _, err := h.Write(msg); err == nil {
+					t.Fatal("write after sum is expected to fail")
+				}
+				if _, err := h.Sum(); err == nil {
+					t.Fatal("sum after sum is expected to fail")
+				}
+				h.Reset()
+			}
+		}
+	}
+	// default suite is the suite of HashToCurve
+	domain := []byte("QUUX-V01-CS02-with-" + SuiteG1XMDSHA256SSWURO)
+	expected, err := g.HashToCurve(msg, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := g.NewHasher(nil, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = h.Write(msg)
+	p, err := h.Sum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Equal(p, expected) {
+		t.Fatal("bad streaming hash with default suite")
+	}
+	s, _ := NewSuite(SuiteG2XMDSHA256SSWURO)
+	if _, err := g.NewHasher(s, domain); err == nil {
+		t.Fatal("suite of other group is expected to be rejected")
+	}
+}
+
+func TestG1HashToCurveBatch(t *testing.T) {
+	g := NewG1()
+	domain := []byte("QUUX-V01-CS02-with-" + SuiteG1XMDSHA256SSWURO)
+	for _, n := range []int{0, 1, 2, 37} {
+		msgs := make([][]byte, n)
+		for i := range msgs {
+			msgs[i] = make([]byte, i)
+			_, _ = rand.Read(msgs[i])
+		}
+		points, err := g.HashToCurveBatch(msgs, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(points) != n {
+			t.Fatal("bad number of points")
+		}
+		for i := range msgs {
+			expected, err := g.HashToCurve(msgs[i], domain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !g.Equal(points[i], expected) || !g.IsAffine(points[i]) {
+				t.Fatalf("bad batch hash to curve at %d of %d", i, n)
+			}
+		}
+	}
+}
+
+func TestG1HashToCurveBatchDegenerate(t *testing.T) {
+	g := NewG1()
+	// messages are taken as the expanded bytes, so that field elements can be chosen
+	expand := func(msg, _ []byte, _ int) ([]byte, error) { return msg, nil }
+	encode := func(u0, u1 *fe) []byte {
+		msg := make([]byte, 128)
+		toBig(u0).FillBytes(msg[:64])
+		toBig(u1).FillBytes(msg[64:])
+		return msg
+	}
+	k := isogenyKernelPointG1(t)
+	kernel := swuMapG1Inverse(&k.x, &k.y)
+	if len(kernel) == 0 {
+		t.Fatal("kernel point is expected to have a preimage")
+	}
+	var msgs [][]byte
+	for i := 0; i < fuz; i++ {
+		u, _ := newRand(rand.Reader)
+		v := new(fe)
+		neg(v, u)
+		msgs = append(msgs,
+			// sum on the isogenous curve is a doubling
+			encode(u, u),
+			// sum is infinity as f(-u) = -f(u)
+			encode(u, v),
+			// one or both points are in the kernel of the isogeny
+			encode(kernel[0], u),
+			encode(kernel[0], kernel[0]),
+		)
+	}
+	points := make([]*PointG1, len(msgs))
+	if err := g.hashToCurveBatch(points, expand, msgs, nil); err != nil {
+		t.Fatal(err)
+	}
+	for i := range msgs {
+		expected, err := g.hashToCurve(expand, msgs[i], nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.Equal(points[i], expected) || !g.IsOnCurve(points[i]) {
+			t.Fatalf("bad batch hash to curve at %d", i)
+		}
+	}
+}
+
+func TestG1SWUMapInverse(t *testing.T) {
+	for i := 0; i < fuz; i++ {
+		u, _ := newRand(rand.Reader)
+		x, y := swuMapG1CT(u)
+		preimages := swuMapG1Inverse(x, y)
+		if len(preimages) > uniformBytesMaxPreimages {
+			t.Fatal("too many preimages")
+		}
+		found := false
+		for _, v := range preimages {
+			x0, y0 := swuMapG1CT(v)
+			if !equal(x0, x) || !equal(y0, y) {
+				t.Fatal("bad preimage")
+			}
+			found = found || equal(u, v)
+		}
+		if !found {
+			t.Fatal("input is expected to be among preimages")
+		}
+	}
+}
+
+func TestG1IsogenyMapInverse(t *testing.T) {
+	g := NewG1()
+	for i := 0; i < 3; i++ {
+		p := g.Affine(g.MulScalar(g.New(), g.One(), randScalar(q)))
+		x, y, err := isogenyMapG1Inverse(&p[0], &p[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		isogenyMapG1(x, y)
+		if !equal(x, &p[0]) || !equal(y, &p[1]) {
+			t.Fatal("bad isogeny inverse")
+		}
+	}
+}
+
+func TestG1UniformBytes(t *testing.T) {
+	g := NewG1()
+	points := []*PointG1{g.Zero(), g.One()}
+	for i := 0; i < 4; i++ {
+		points = append(points, g.MulScalar(g.New(), g.One(), randScalar(q)))
+	}
+	var top [2]int
+	for _, p := range points {
+		for j := 0; j < 4; j++ {
+			b, err := g.ToUniformBytes(p, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(b) != 96 {
+				t.Fatal("bad encoding length")
+			}
+			// field elements alone would leave top three bits zero
+			top[0] += int(b[0] >> 7)
+			top[1] += int(b[48] >> 7)
+			r, err := g.FromUniformBytes(b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !g.Equal(r, p) {
+				t.Fatal("bad encoding round trip")
+			}
+		}
+	}
+	if top[0] == 0 || top[1] == 0 {
+		t.Fatal("encoding is expected to use all bits")
+	}
+	for i := 0; i < fuz; i++ {
+		b := make([]byte, 96)
+		_, _ = rand.Read(b)
+		p, err := g.FromUniformBytes(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.InCorrectSubgroup(p) {
+			t.Fatal("decoded point is expected to be in correct subgroup")
+		}
+	}
+	if _, err := g.FromUniformBytes(make([]byte, 95)); err == nil {
+		t.Fatal("short input is expected to fail")
+	}
+	u, _ := newRand(rand.Reader)
+	x, y := swuMapG1CT(u)
+	isogenyMapG1(x, y)
+	if _, err := g.ToUniformBytes(&PointG1{*x, *y, *one()}, nil); err == nil {
+		t.Fatal("point out of subgroup is expected to fail")
+	}
+}
+
+// isogenyKernelPointG1 returns a point of the isogenous curve in the kernel of the isogeny
+// where x is a root of xDen.
+func isogenyKernelPointG1(t *testing.T) *isoPointG1 {
+	xDen := make([]fe, 16)
+	for i := range xDen {
+		xDen[i].set(isogenyConstansG1[1][i])
+	}
+	x, err := polyRoot(polyMonic(polyTrim(xDen)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, rhs := &isoPointG1{x: *x}, new(fe)
+	square(rhs, x)
+	add(rhs, rhs, swuParamsForG1.a)
+	mul(rhs, rhs, x)
+	add(rhs, rhs, swuParamsForG1.b)
+	if !sqrt(&k.y, rhs) {
+		t.Fatal("kernel point is expected to be rational")
+	}
+	return k
+}
+
+func TestG1UniformBytesIsogenyKernel(t *testing.T) {
+	g := NewG1()
+	k := isogenyKernelPointG1(t)
+	for i := 0; i < fuz; i++ {
+		// find u and v where f(u) + f(v) is the kernel point
+		var u *fe
+		var preimages []*fe
+		for len(preimages) == 0 {
+			u, _ = newRand(rand.Reader)
+			x0, y0 := swuMapG1CT(u)
+			d := &isoPointG1{x: *x0, y: *y0}
+			d.neg(d)
+			d.add(k, d)
+			preimages = swuMapG1Inverse(&d.x, &d.y)
+		}
+		in := make([]byte, 96)
+		toBig(u).FillBytes(in[:48])
+		toBig(preimages[0]).FillBytes(in[48:])
+		p, err := g.FromUniformBytes(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.IsZero(p) || !g.IsOnCurve(p) || !g.InCorrectSubgroup(p) {
+			t.Fatal("kernel point of the isogeny is expected to be decoded to infinity")
+		}
+	}
+}
+
+func TestG1SWUMapConstantTime(t *testing.T) {
+	g := NewG1()
+	inputs := []*fe{zero(), one(), new(fe).set(swuParamsForG1.z)}
+	neg(inputs[2], inputs[2])
+	for i := 0; i < fuz; i++ {
+		u, _ := newRand(rand.Reader)
+		inputs = append(inputs, u)
+	}
+	for _, u := range inputs {
+		x0, y0 := swuMapG1(u)
+		x1, y1 := swuMapG1CT(u)
+		if !equal(x0, x1) || !equal(y0, y1) {
+			t.Fatalf("constant time map doesn't match the reference for %s", u)
+		}
+		isogenyMapG1(x1, y1)
+		if !g.IsOnCurve(&PointG1{*x1, *y1, *one()}) {
+			t.Fatal("mapped point is not on curve")
+		}
+	}
+}
+
+func TestG1ConstantTimeArithmetic(t *testing.T) {
+	g := NewG1()
+	// homogeneous projective form of a point given in Jacobian coordinates is (X * Z : Y : Z^3)
+	projective := func(p *PointG1) *PointG1 {
+		r := g.New().Set(p)
+		mul(&r[0], &p[0], &p[2])
+		square(&r[2], &p[2])
+		mul(&r[2], &r[2], &p[2])
+		return r
+	}
+	for i := 0; i < fuz; i++ {
+		a, b := g.rand(), g.rand()
+		cases := [][3]*PointG1{
+			{a, b, g.Add(g.New(), a, b)},
+			{a, a, g.Double(g.New(), a)},
+			{a, g.Neg(g.New(), a), g.Zero()},
+			{a, g.Zero(), a},
+			{g.Zero(), b, b},
+			{g.Zero(), g.Zero(), g.Zero()},
+		}
+		for j, c := range cases {
+			r := g.addCT(g.New(), projective(c[0]), projective(c[1]))
+			if !g.Equal(g.affineCT(r), c[2]) {
+				t.Fatalf("bad constant time addition at case %d", j)
+			}
+			r = g.doubleCT(g.New(), projective(c[0]))
+			if !g.Equal(g.affineCT(r), g.Double(g.New(), c[0])) {
+				t.Fatalf("bad constant time doubling at case %d", j)
+			}
+		}
+		r := g.affineCT(projective(a))
+		if !g.IsAffine(r) || !g.Equal(r, g.Affine(g.New().Set(a))) {
+			t.Fatal("bad constant time affine conversion")
+		}
+		r = g.affineCT(g.Zero())
+		if !g.IsZero(r) || !equal(&r[1], one()) {
+			t.Fatal("bad constant time affine conversion of infinity")
+		}
+		u, _ := newRand(rand.Reader)
+		x, y := swuMapG1CT(u)
+		p := g.New()
+		isogenyMapG1Projective(p, x, y)
+		isogenyMapG1(x, y)
+		expected := &PointG1{*x, *y, *one()}
+		if !g.Equal(g.affineCT(g.New().Set(p)), expected) {
+			t.Fatal("projective isogeny map doesn't match the reference")
+		}
+		g.clearCofactorCT(p)
+		g.ClearCofactor(expected)
+		if !g.Equal(g.affineCT(p), expected) {
+			t.Fatal("constant time cofactor clearing doesn't match the reference")
+		}
+	}
+}
+
 func BenchmarkG1Add(t *testing.B) {
 	g1 := NewG1()
 	a, b, c := g1.rand(), g1.rand(), PointG1{}
@@ -403,6 +1036,32 @@ func BenchmarkG1Mul(t *testing.B) {
 	}
 }
 
+func BenchmarkG1SubgroupCheck(t *testing.B) {
+	g := NewG1()
+	a := g.rand()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		_ = g.InCorrectSubgroup(a)
+	}
+}
+
+func BenchmarkG1AffineBatch(t *testing.B) {
+	g := NewG1()
+	n := 64
+	points, copies := make([]*PointG1, n), make([]*PointG1, n)
+	for i := 0; i < n; i++ {
+		points[i] = g.rand()
+		copies[i] = g.New()
+	}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		for j := 0; j < n; j++ {
+			copies[j].Set(points[j])
+		}
+		g.AffineBatch(copies)
+	}
+}
+
 func BenchmarkG1MapToCurve(t *testing.B) {
 	a := fromHex(48, "0x1234")
 	g1 := NewG1()
@@ -414,3 +1073,63 @@ func BenchmarkG1MapToCurve(t *testing.B) {
 		}
 	}
 }
+
+func BenchmarkG1SWUMapConstantTime(t *testing.B) {
+	u, _ := newRand(rand.Reader)
+	t.Run("Reference", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			swuMapG1(u)
+		}
+	})
+	t.Run("ConstantTime", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			swuMapG1CT(u)
+		}
+	})
+}
+
+func BenchmarkG1HashToCurveBatch(t *testing.B) {
+	g := NewG1()
+	domain := []byte("QUUX-V01-CS02-with-" + SuiteG1XMDSHA256SSWURO)
+	msgs := make([][]byte, 128)
+	for i := range msgs {
+		msgs[i] = make([]byte, 32)
+		_, _ = rand.Read(msgs[i])
+	}
+	t.Run("Serial", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			for j := range msgs {
+				if _, err := g.HashToCurve(msgs[j], domain); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	})
+	t.Run("Batch", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			if _, err := g.HashToCurveBatch(msgs, domain); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkG1UniformBytes(t *testing.B) {
+	g := NewG1()
+	p := g.MulScalar(g.New(), g.One(), randScalar(q))
+	b, _ := g.ToUniformBytes(p, nil)
+	t.Run("Encode", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			if _, err := g.ToUniformBytes(p, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	t.Run("Decode", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			if _, err := g.FromUniformBytes(b); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
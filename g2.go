@@ -2,8 +2,11 @@ package bls12381
 
 import (
 	"fmt"
+	"hash"
 	"math"
 	"math/big"
+	"runtime"
+	"sync"
 )
 
 // PointG2 is type for point in G2.
@@ -291,10 +294,27 @@ func (g *G2) Equal(p1, p2 *PointG2) bool {
 }
 
 // InCorrectSubgroup checks whether given point is in correct subgroup.
+// It checks ψ(P) == x * P instead of multiplying by the group order.
+// https://eprint.iacr.org/2021/1130
 func (g *G2) InCorrectSubgroup(p *PointG2) bool {
-	tmp := &PointG2{}
-	g.MulScalar(tmp, p, q)
-	return g.IsZero(tmp)
+	if g.IsZero(p) {
+		return true
+	}
+	t0, t1 := &PointG2{}, &PointG2{}
+	// x is negative
+	g.MulScalar(t0, p, x)
+	g.Neg(t0, t0)
+	g.psi(t1, p)
+	return g.Equal(t0, t1)
+}
+
+// psi sets c = ψ(p), untwist-Frobenius-twist endomorphism.
+func (g *G2) psi(c, p *PointG2) {
+	g.f.conjugate(&c[0], &p[0])
+	g.f.conjugate(&c[1], &p[1])
+	g.f.conjugate(&c[2], &p[2])
+	g.f.mul(&c[0], &c[0], psiX)
+	g.f.mul(&c[1], &c[1], psiY)
 }
 
 // IsOnCurve checks a G2 point is on curve.
@@ -336,6 +356,46 @@ func (g *G2) Affine(p *PointG2) *PointG2 {
 	return p
 }
 
+// AffineBatch converts given points to affine form in place.
+// It shares a single field inversion among all points and multiplies coordinates in batches.
+func (g *G2) AffineBatch(p []*PointG2) {
+	idx := make([]int, 0, len(p))
+	for i := range p {
+		if !g.IsZero(p[i]) && !g.IsAffine(p[i]) {
+			idx = append(idx, i)
+		}
+	}
+	n := len(idx)
+	if n == 0 {
+		return
+	}
+	// (z0 + z1 * u)^-1 = (z0 - z1 * u) / (z0^2 + z1^2)
+	buf := make([]fe, 4*n)
+	z0, z1, t0, t1 := buf[:n], buf[n:2*n], buf[2*n:3*n], buf[3*n:]
+	for i, j := range idx {
+		z0[i].set(&p[j][2][0])
+		z1[i].set(&p[j][2][1])
+	}
+	mulBatch(t0, z0, z0)
+	mulBatch(t1, z1, z1)
+	for i := range t0 {
+		addAssign(&t0[i], &t1[i])
+	}
+	inverseBatch(t0)
+	mulBatch(z0, z0, t0)
+	mulBatch(z1, z1, t0)
+	t := g.t
+	for i, j := range idx {
+		t[0][0].set(&z0[i])
+		neg(&t[0][1], &z1[i])
+		g.f.square(t[1], t[0])
+		g.f.mul(&p[j][0], &p[j][0], t[1])
+		g.f.mul(t[0], t[0], t[1])
+		g.f.mul(&p[j][1], &p[j][1], t[0])
+		g.f.copy(&p[j][2], g.f.one())
+	}
+}
+
 // Add adds two G2 points p1, p2 and assigns the result to point at first argument.
 func (g *G2) Add(r, p1, p2 *PointG2) *PointG2 {
 	// http://www.hyperelliptic.org/EFD/gp/auto-shortw-jacobian-0.html#addition-add-2007-bl
@@ -451,8 +511,186 @@ func (g *G2) MulScalar(c, p *PointG2, e *big.Int) *PointG2 {
 }
 
 // ClearCofactor maps given a G2 point to correct subgroup
+// Result is the same with multiplication by the effective cofactor h_eff, but it is calculated
+// with the endomorphism ψ as [x^2 - x - 1]P + [x - 1]ψ(P) + ψ^2(2P) with x the negative curve parameter.
+// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-G.3
 func (g *G2) ClearCofactor(p *PointG2) {
-	g.MulScalar(p, p, cofactorEFFG2)
+	t1, t2, t3 := g.New(), g.New(), g.New()
+	// t1 = c1 * P where c1 = -|x|
+	g.MulScalar(t1, p, x)
+	g.Neg(t1, t1)
+	g.psi(t2, p)
+	g.Double(t3, p)
+	g.psi(t3, t3)
+	g.psi(t3, t3)
+	g.Sub(t3, t3, t2)
+	g.Add(t2, t1, t2)
+	g.MulScalar(t2, t2, x)
+	g.Neg(t2, t2)
+	g.Add(t3, t3, t2)
+	g.Sub(t3, t3, t1)
+	g.Sub(p, t3, p)
+}
+
+// Constant time operations below are used in hashing to curve and they take points in
+// homogeneous projective coordinates (X : Y : Z) where x = X / Z, point at infinity is (0 : 1 : 0).
+// Complete formulas of the curve are valid for any input since the curve has no point of order two.
+// https://eprint.iacr.org/2015/1060
+
+// mulByB3 sets c = 3 * b * a where b = 4 * (1 + u).
+func (g *G2) mulByB3(c, a *fe2) {
+	t := g.t[8]
+	g.f.mulByB(t, a)
+	g.f.double(c, t)
+	g.f.add(c, c, t)
+}
+
+// addCT sets r = p1 + p2 with complete formula, Algorithm 7 in https://eprint.iacr.org/2015/1060
+func (g *G2) addCT(r, p1, p2 *PointG2) *PointG2 {
+	f, t := g.f, g.t
+	t0, t1, t2, t3, t4 := t[0], t[1], t[2], t[3], t[4]
+	x3, y3, z3 := t[5], t[6], t[7]
+	f.mul(t0, &p1[0], &p2[0])
+	f.mul(t1, &p1[1], &p2[1])
+	f.mul(t2, &p1[2], &p2[2])
+	f.add(t3, &p1[0], &p1[1])
+	f.add(t4, &p2[0], &p2[1])
+	f.mul(t3, t3, t4)
+	f.add(t4, t0, t1)
+	f.sub(t3, t3, t4)
+	f.add(t4, &p1[1], &p1[2])
+	f.add(x3, &p2[1], &p2[2])
+	f.mul(t4, t4, x3)
+	f.add(x3, t1, t2)
+	f.sub(t4, t4, x3)
+	f.add(x3, &p1[0], &p1[2])
+	f.add(y3, &p2[0], &p2[2])
+	f.mul(x3, x3, y3)
+	f.add(y3, t0, t2)
+	f.sub(y3, x3, y3)
+	f.double(x3, t0)
+	f.add(t0, x3, t0)
+	g.mulByB3(t2, t2)
+	f.add(z3, t1, t2)
+	f.sub(t1, t1, t2)
+	g.mulByB3(y3, y3)
+	f.mul(x3, t4, y3)
+	f.mul(t2, t3, t1)
+	f.sub(x3, t2, x3)
+	f.mul(y3, y3, t0)
+	f.mul(t1, t1, z3)
+	f.add(y3, t1, y3)
+	f.mul(t0, t0, t3)
+	f.mul(z3, z3, t4)
+	f.add(z3, z3, t0)
+	f.copy(&r[0], x3)
+	f.copy(&r[1], y3)
+	f.copy(&r[2], z3)
+	return r
+}
+
+// doubleCT sets r = 2 * p with complete formula, Algorithm 9 in https://eprint.iacr.org/2015/1060
+func (g *G2) doubleCT(r, p *PointG2) *PointG2 {
+	f, t := g.f, g.t
+	t0, t1, t2 := t[0], t[1], t[2]
+	x3, y3, z3 := t[5], t[6], t[7]
+	f.square(t0, &p[1])
+	f.double(z3, t0)
+	f.double(z3, z3)
+	f.double(z3, z3)
+	f.mul(t1, &p[1], &p[2])
+	f.square(t2, &p[2])
+	g.mulByB3(t2, t2)
+	f.mul(x3, t2, z3)
+	f.add(y3, t0, t2)
+	f.mul(z3, t1, z3)
+	f.double(t1, t2)
+	f.add(t2, t1, t2)
+	f.sub(t0, t0, t2)
+	f.mul(y3, t0, y3)
+	f.add(y3, x3, y3)
+	f.mul(t1, &p[0], &p[1])
+	f.mul(x3, t0, t1)
+	f.double(x3, x3)
+	f.copy(&r[0], x3)
+	f.copy(&r[1], y3)
+	f.copy(&r[2], z3)
+	return r
+}
+
+// mulScalarCT sets c = e * p with complete formulas. Scalar is expected to be public,
+// running time depends only on its bits.
+func (g *G2) mulScalarCT(c, p *PointG2, e *big.Int) *PointG2 {
+	r, n := g.Zero(), new(PointG2).Set(p)
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		g.doubleCT(r, r)
+		if e.Bit(i) == 1 {
+			g.addCT(r, r, n)
+		}
+	}
+	return c.Set(r)
+}
+
+// clearCofactorCT maps given point to correct subgroup as ClearCofactor does.
+// psi acts on projective coordinates in the same way as on Jacobian ones.
+func (g *G2) clearCofactorCT(p *PointG2) {
+	t1, t2, t3, t4 := g.New(), g.New(), g.New(), g.New()
+	// t1 = c1 * P where c1 = -|x|
+	g.mulScalarCT(t1, p, x)
+	g.negCT(t1, t1)
+	g.psiCT(t2, p)
+	g.doubleCT(t3, p)
+	g.psiCT(t3, t3)
+	g.psiCT(t3, t3)
+	g.addCT(t3, t3, g.negCT(t4, t2))
+	g.addCT(t2, t1, t2)
+	g.mulScalarCT(t2, t2, x)
+	g.negCT(t2, t2)
+	g.addCT(t3, t3, t2)
+	g.addCT(t3, t3, g.negCT(t4, t1))
+	g.addCT(p, t3, g.negCT(t4, p))
+}
+
+// negCT sets r = -p. Unlike Neg it doesn't branch on zero coordinates.
+func (g *G2) negCT(r, p *PointG2) *PointG2 {
+	g.f.copy(&r[0], &p[0])
+	g.f.sub(&r[1], g.f.zero(), &p[1])
+	g.f.copy(&r[2], &p[2])
+	return r
+}
+
+// psiCT is psi with conjugation done as subtraction from zero so that it doesn't branch on zero coordinates.
+func (g *G2) psiCT(c, p *PointG2) {
+	for i := 0; i < 3; i++ {
+		c[i][0].set(&p[i][0])
+		sub(&c[i][1], zero(), &p[i][1])
+	}
+	g.f.mul(&c[0], &c[0], psiX)
+	g.f.mul(&c[1], &c[1], psiY)
+}
+
+// affineCT converts a point to affine form with Fermat inversion.
+// Result is in the same form that Affine returns.
+func (g *G2) affineCT(p *PointG2) *PointG2 {
+	f, t := g.f, g.t
+	isInfinity := f.isZeroCT(&p[2])
+	f.inverseCT(t[0], &p[2])
+	f.mul(&p[0], &p[0], t[0])
+	f.mul(t[1], &p[1], t[0])
+	f.cmov(&p[1], t[1], f.one(), isInfinity)
+	f.cmov(&p[2], f.one(), f.zero(), isInfinity)
+	return p
+}
+
+// projectiveToJacobian converts a point in homogeneous projective coordinates to Jacobian coordinates.
+// Jacobian form of (X : Y : Z) is (X * Z : Y * Z^2 : Z).
+func (g *G2) projectiveToJacobian(p *PointG2) *PointG2 {
+	f, t := g.f, g.t
+	f.mul(&p[0], &p[0], &p[2])
+	f.square(t[0], &p[2])
+	f.mul(t[0], &p[1], t[0])
+	f.cmov(&p[1], t[0], f.one(), f.isZeroCT(&p[2]))
+	return p
 }
 
 // MultiExp calculates multi exponentiation. Given pairs of G2 point and scalar values
@@ -515,7 +753,7 @@ func (g *G2) MultiExp(r *PointG2, points []*PointG2, powers []*big.Int) (*PointG
 
 // MapToCurve given a byte slice returns a valid G2 point.
 // This mapping function implements the Simplified Shallue-van de Woestijne-Ulas method.
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-05#section-6.6.2
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-6.6.2
 // Input byte slice should be a valid field element, otherwise an error is returned.
 func (g *G2) MapToCurve(in []byte) (*PointG2, error) {
 	fp2 := g.f
@@ -523,51 +761,243 @@ func (g *G2) MapToCurve(in []byte) (*PointG2, error) {
 	if err != nil {
 		return nil, err
 	}
-	x, y := swuMapG2(fp2, u)
-	isogenyMapG2(fp2, x, y)
-	one := fp2.one()
-	q := &PointG2{*x, *y, *one}
-	g.ClearCofactor(q)
-	return g.Affine(q), nil
+	x, y := swuMapG2CT(fp2, u)
+	q := g.New()
+	isogenyMapG2Projective(fp2, q, x, y)
+	g.clearCofactorCT(q)
+	return g.affineCT(q), nil
 }
 
 // EncodeToCurve given a message and domain seperator tag returns the hash result
 // which is a valid curve point.
-// Implementation follows BLS12381G1_XMD:SHA-256_SSWU_NU_ suite at
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
+// Implementation follows BLS12381G2_XMD:SHA-256_SSWU_NU_ suite at
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-8.8.2
+// Field and point operations on the message hash don't branch on it.
 func (g *G2) EncodeToCurve(msg, domain []byte) (*PointG2, error) {
-	hashRes, err := hashToFpXMDSHA256(msg, domain, 2)
+	return g.encodeToCurve(expandMsgSHA256XMD, msg, domain)
+}
+
+// HashToCurve given a message and domain seperator tag returns the hash result
+// which is a valid curve point.
+// Implementation follows BLS12381G2_XMD:SHA-256_SSWU_RO_ suite at
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-8.8.2
+// Field and point operations on the message hash don't branch on it.
+func (g *G2) HashToCurve(msg, domain []byte) (*PointG2, error) {
+	return g.hashToCurve(expandMsgSHA256XMD, msg, domain)
+}
+
+// EncodeToCurveWith is the same as EncodeToCurve but it uses expand_message_xmd with given hash function.
+// Output size of the hash function should be at least 32 bytes.
+func (g *G2) EncodeToCurveWith(hashFn func() hash.Hash, msg, domain []byte) (*PointG2, error) {
+	return g.encodeToCurve(ExpandMsgXMD(hashFn), msg, domain)
+}
+
+// HashToCurveWith is the same as HashToCurve but it uses expand_message_xmd with given hash function
+// such as SHA-512 for a BLS12381G2_XMD:SHA-512_SSWU_RO_ suite.
+// Output size of the hash function should be at least 32 bytes.
+func (g *G2) HashToCurveWith(hashFn func() hash.Hash, msg, domain []byte) (*PointG2, error) {
+	return g.hashToCurve(ExpandMsgXMD(hashFn), msg, domain)
+}
+
+// EncodeToCurveSHAKE128 is the same as EncodeToCurve but it uses
+// expand_message_xof with SHAKE128 that is BLS12381G2_XOF:SHAKE-128_SSWU_NU_ suite.
+func (g *G2) EncodeToCurveSHAKE128(msg, domain []byte) (*PointG2, error) {
+	return g.encodeToCurve(expandMsgSHAKE128XOF, msg, domain)
+}
+
+// HashToCurveSHAKE128 is the same as HashToCurve but it uses
+// expand_message_xof with SHAKE128 that is BLS12381G2_XOF:SHAKE-128_SSWU_RO_ suite.
+func (g *G2) HashToCurveSHAKE128(msg, domain []byte) (*PointG2, error) {
+	return g.hashToCurve(expandMsgSHAKE128XOF, msg, domain)
+}
+
+// EncodeToCurveSHAKE256 is the same as EncodeToCurve but it uses
+// expand_message_xof with SHAKE256 that is BLS12381G2_XOF:SHAKE-256_SSWU_NU_ suite.
+func (g *G2) EncodeToCurveSHAKE256(msg, domain []byte) (*PointG2, error) {
+	return g.encodeToCurve(expandMsgSHAKE256XOF, msg, domain)
+}
+
+// HashToCurveSHAKE256 is the same as HashToCurve but it uses
+// expand_message_xof with SHAKE256 that is BLS12381G2_XOF:SHAKE-256_SSWU_RO_ suite.
+func (g *G2) HashToCurveSHAKE256(msg, domain []byte) (*PointG2, error) {
+	return g.hashToCurve(expandMsgSHAKE256XOF, msg, domain)
+}
+
+// HashToCurveWithSuite hashes given message to a curve point as the suite defines,
+// running hash_to_curve for RO and encode_to_curve for NU suites.
+// Nil suites, suites that are not created with NewSuite and suites of the other group are rejected.
+func (g *G2) HashToCurveWithSuite(s *Suite, msg, domain []byte) (*PointG2, error) {
+	if err := s.check(2); err != nil {
+		return nil, err
+	}
+	if s.RandomOracle {
+		return g.hashToCurve(s.expand, msg, domain)
+	}
+	return g.encodeToCurve(s.expand, msg, domain)
+}
+
+// G2Hasher hashes a message that is written in pieces to a G2 point.
+// Message is not buffered since it is needed only once while it is expanded.
+// A hasher is not safe for concurrent use.
+type G2Hasher struct {
+	g            *G2
+	stream       messageStream
+	randomOracle bool
+	done         bool
+}
+
+// NewHasher returns a hasher for given suite and domain separation tag.
+// If suite is nil SuiteG2XMDSHA256SSWURO suite is used which is the suite of HashToCurve.
+func (g *G2) NewHasher(s *Suite, domain []byte) (*G2Hasher, error) {
+	if s == nil {
+		s, _ = NewSuite(SuiteG2XMDSHA256SSWURO)
+	}
+	if err := s.check(2); err != nil {
+		return nil, err
+	}
+	stream, err := s.newStream(domain)
+	if err != nil {
+		return nil, err
+	}
+	return &G2Hasher{g: g, stream: stream, randomOracle: s.RandomOracle}, nil
+}
+
+// Write adds more data to the message. It fails if Sum is already called.
+func (h *G2Hasher) Write(msg []byte) (int, error) {
+	if h.done {
+		return 0, fmt.Errorf("hasher is finalized")
+	}
+	return h.stream.Write(msg)
+}
+
+// Sum returns the hash of the message written so far.
+// Unlike hash.Hash the hasher is finalized and it should be reset before it is used again.
+func (h *G2Hasher) Sum() (*PointG2, error) {
+	if h.done {
+		return nil, fmt.Errorf("hasher is finalized")
+	}
+	h.done = true
+	// message and domain are already in the stream
+	expand := func(_, _ []byte, outLen int) ([]byte, error) {
+		return h.stream.expand(outLen)
+	}
+	if h.randomOracle {
+		return h.g.hashToCurve(expand, nil, nil)
+	}
+	return h.g.encodeToCurve(expand, nil, nil)
+}
+
+// Reset discards the message written so far and keeps the suite and the domain separation tag.
+func (h *G2Hasher) Reset() {
+	h.stream.reset()
+	h.done = false
+}
+
+func (g *G2) encodeToCurve(expand Expander, msg, domain []byte) (*PointG2, error) {
+	hashRes, err := hashToFp(expand, msg, domain, 2)
 	if err != nil {
 		return nil, err
 	}
 	fp2 := g.f
 	u := &fe2{*hashRes[0], *hashRes[1]}
-	x, y := swuMapG2(fp2, u)
-	isogenyMapG2(fp2, x, y)
-	one := fp2.one()
-	q := &PointG2{*x, *y, *one}
-	g.ClearCofactor(q)
-	return g.Affine(q), nil
+	x, y := swuMapG2CT(fp2, u)
+	q := g.New()
+	isogenyMapG2Projective(fp2, q, x, y)
+	g.clearCofactorCT(q)
+	return g.affineCT(q), nil
 }
 
-// HashToCurve given a message and domain seperator tag returns the hash result
-// which is a valid curve point.
-// Implementation follows BLS12381G1_XMD:SHA-256_SSWU_RO_ suite at
-// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06
-func (g *G2) HashToCurve(msg, domain []byte) (*PointG2, error) {
-	hashRes, err := hashToFpXMDSHA256(msg, domain, 4)
+func (g *G2) hashToCurve(expand Expander, msg, domain []byte) (*PointG2, error) {
+	hashRes, err := hashToFp(expand, msg, domain, 4)
 	if err != nil {
 		return nil, err
 	}
 	fp2 := g.f
 	u0, u1 := &fe2{*hashRes[0], *hashRes[1]}, &fe2{*hashRes[2], *hashRes[3]}
-	x0, y0 := swuMapG2(fp2, u0)
-	x1, y1 := swuMapG2(fp2, u1)
-	one := fp2.one()
-	p0, p1 := &PointG2{*x0, *y0, *one}, &PointG2{*x1, *y1, *one}
-	g.Add(p0, p0, p1)
-	g.Affine(p0)
-	isogenyMapG2(fp2, &p0[0], &p0[1])
-	g.ClearCofactor(p0)
-	return g.Affine(p0), nil
+	x0, y0 := swuMapG2CT(fp2, u0)
+	x1, y1 := swuMapG2CT(fp2, u1)
+	p0, p1 := g.New(), g.New()
+	isogenyMapG2Projective(fp2, p0, x0, y0)
+	isogenyMapG2Projective(fp2, p1, x1, y1)
+	g.addCT(p0, p0, p1)
+	g.clearCofactorCT(p0)
+	return g.affineCT(p0), nil
+}
+
+// HashToCurveBatch hashes each message as HashToCurve does with the same domain separation tag.
+// Field inversions of the map and the affine conversions are shared among messages
+// with Montgomery's trick and messages are split across GOMAXPROCS goroutines.
+// Unlike HashToCurve, shared inversions are not constant time.
+func (g *G2) HashToCurveBatch(msgs [][]byte, domain []byte) ([]*PointG2, error) {
+	out := make([]*PointG2, len(msgs))
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(msgs) {
+		workers = len(msgs)
+	}
+	if workers < 2 {
+		if err := g.hashToCurveBatch(out, expandMsgSHA256XMD, msgs, domain); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+	// each worker has its own temporaries and the caller takes the first part
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 1; i < workers; i++ {
+		lo, hi := i*len(msgs)/workers, (i+1)*len(msgs)/workers
+		wg.Add(1)
+		go func(i, lo, hi int) {
+			defer wg.Done()
+			errs[i] = NewG2().hashToCurveBatch(out[lo:hi], expandMsgSHA256XMD, msgs[lo:hi], domain)
+		}(i, lo, hi)
+	}
+	hi := len(msgs) / workers
+	errs[0] = g.hashToCurveBatch(out[:hi], expandMsgSHA256XMD, msgs[:hi], domain)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// hashToCurveBatch is the serial part of HashToCurveBatch that writes points to out.
+func (g *G2) hashToCurveBatch(out []*PointG2, expand Expander, msgs [][]byte, domain []byte) error {
+	n := len(msgs)
+	if n == 0 {
+		return nil
+	}
+	fp2 := g.f
+	// map u0 and u1 of all messages and invert x denominators together
+	x, den, y := make([]fe2, 2*n), make([]fe2, 2*n), make([]fe2, 2*n)
+	for i := range msgs {
+		u, err := hashToFp(expand, msgs[i], domain, 4)
+		if err != nil {
+			return err
+		}
+		for j := 0; j < 2; j++ {
+			xNum, xDen, yj := swuMapG2Fraction(fp2, &fe2{*u[2*j], *u[2*j+1]})
+			fp2.copy(&x[2*i+j], xNum)
+			fp2.copy(&den[2*i+j], xDen)
+			fp2.copy(&y[2*i+j], yj)
+		}
+	}
+	fp2.inverseBatch(den)
+	for i := range x {
+		fp2.mul(&x[i], &x[i], &den[i])
+	}
+	// isogeny is applied in projective form, so that points in its kernel are mapped to infinity
+	// and the sum is taken on the curve of G2
+	points := make([]*PointG2, n)
+	for i := range points {
+		p0, p1 := g.New(), g.New()
+		isogenyMapG2Projective(fp2, p0, &x[2*i], &y[2*i])
+		isogenyMapG2Projective(fp2, p1, &x[2*i+1], &y[2*i+1])
+		points[i] = g.Add(p0, g.projectiveToJacobian(p0), g.projectiveToJacobian(p1))
+		g.ClearCofactor(points[i])
+	}
+	g.AffineBatch(points)
+	copy(out, points)
+	return nil
 }
@@ -3,8 +3,13 @@ package bls12381
 import (
 	"bytes"
 	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
 	"io/ioutil"
 	"math/big"
+	mrand "math/rand"
+	"strings"
 	"testing"
 )
 
@@ -37,6 +42,20 @@ func (g *G2) new() *PointG2 {
 	return g.Zero()
 }
 
+func (g *G2) randCurvePoint() *PointG2 {
+	// point on curve which is not in correct subgroup with overwhelming probability
+	for {
+		x, _ := g.f.rand(rand.Reader)
+		y := g.f.new()
+		g.f.square(y, x)
+		g.f.mul(y, y, x)
+		g.f.add(y, y, b2)
+		if g.f.sqrt(y, y) {
+			return &PointG2{*x, *y, *g.f.one()}
+		}
+	}
+}
+
 func TestG2Serialization(t *testing.T) {
 	var err error
 	g2 := NewG2()
@@ -297,6 +316,82 @@ func TestG2MultiExpBatch(t *testing.T) {
 	}
 }
 
+func TestG2SubgroupCheck(t *testing.T) {
+	g := NewG2()
+	if !g.InCorrectSubgroup(g.Zero()) || !g.InCorrectSubgroup(g.One()) {
+		t.Fatalf("generator and point at infinity are expected to be in correct subgroup")
+	}
+	for i := 0; i < fuz; i++ {
+		if !g.InCorrectSubgroup(g.rand()) {
+			t.Fatalf("point is expected to be in correct subgroup")
+		}
+		p := g.randCurvePoint()
+		if !g.IsOnCurve(p) {
+			t.Fatalf("point is expected to be on curve")
+		}
+		// cross check against multiplication by group order
+		r := g.New()
+		g.MulScalar(r, p, q)
+		if g.InCorrectSubgroup(p) != g.IsZero(r) {
+			t.Fatalf("fast subgroup check doesn't match")
+		}
+		// cofactor cleared point is in correct subgroup
+		g.ClearCofactor(p)
+		if !g.InCorrectSubgroup(p) {
+			t.Fatalf("point is expected to be in correct subgroup after clearing cofactor")
+		}
+	}
+}
+
+func TestG2ClearCofactor(t *testing.T) {
+	g := NewG2()
+	for i := 0; i < fuz; i++ {
+		u, _ := g.f.rand(rand.Reader)
+		x, y := swuMapG2(g.f, u)
+		isogenyMapG2(g.f, x, y)
+		p := &PointG2{*x, *y, *g.f.one()}
+		expected := g.MulScalar(g.New(), p, cofactorEFFG2)
+		g.ClearCofactor(p)
+		if !g.Equal(p, expected) {
+			t.Fatal("bad cofactor clearing")
+		}
+		if !g.InCorrectSubgroup(p) {
+			t.Fatal("point is expected to be in correct subgroup")
+		}
+	}
+}
+
+func TestG2AffineBatch(t *testing.T) {
+	g := NewG2()
+	n := fuz + 3
+	points := make([]*PointG2, n)
+	for i := 0; i < n; i++ {
+		points[i] = g.rand()
+	}
+	points[0] = g.Zero()
+	points[1] = g.randAffine()
+	copies := make([]*PointG2, n)
+	for i := 0; i < n; i++ {
+		copies[i] = g.New().Set(points[i])
+	}
+	g.AffineBatch(points)
+	for j := 0; j < n; j++ {
+		expected := g.Affine(copies[j])
+		if g.IsZero(expected) {
+			if !g.IsZero(points[j]) {
+				t.Fatalf("point at infinity is expected")
+			}
+			continue
+		}
+		if !g.IsAffine(points[j]) {
+			t.Fatalf("point is not in affine form")
+		}
+		if !(g.f.equal(&points[j][0], &expected[0]) && g.f.equal(&points[j][1], &expected[1])) {
+			t.Fatalf("batch affine conversion doesn't match")
+		}
+	}
+}
+
 func TestG2EncodeToCurve(t *testing.T) {
 	domain := []byte("BLS12381G2_XMD:SHA-256_SSWU_NU_TESTGEN")
 	for i, v := range []struct {
@@ -405,6 +500,442 @@ func TestG2HashToCurve(t *testing.T) {
 	}
 }
 
+func TestG2HashToCurveRFC9380(t *testing.T) {
+	// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-J.10
+	for _, suite := range []struct {
+		suite   string
+		hash    func(g *G2, msg, domain []byte) (*PointG2, error)
+		vectors []struct {
+			msg      []byte
+			expected []byte
+		}
+	}{
+		{
+			suite: SuiteG2XMDSHA256SSWURO,
+			hash:  (*G2).HashToCurve,
+			vectors: []struct {
+				msg      []byte
+				expected []byte
+			}{
+				{
+					msg: []byte(""),
+					expected: fromHex(-1,
+						"05cb8437535e20ecffaef7752baddf98034139c38452458baeefab379ba13dff5bf5dd71b72418717047f5b0f37da03d",
+						"0141ebfbdca40eb85b87142e130ab689c673cf60f1a3e98d69335266f30d9b8d4ac44c1038e9dcdd5393faf5c41fb78a",
+						"12424ac32561493f3fe3c260708a12b7c620e7be00099a974e259ddc7d1f6395c3c811cdd19f1e8dbf3e9ecfdcbab8d6",
+						"0503921d7f6a12805e72940b963c0cf3471c7b2a524950ca195d11062ee75ec076daf2d4bc358c4b190c0c98064fdd92",
+					),
+				},
+				{
+					msg: []byte("abc"),
+					expected: fromHex(-1,
+						"139cddbccdc5e91b9623efd38c49f81a6f83f175e80b06fc374de9eb4b41dfe4ca3a230ed250fbe3a2acf73a41177fd8",
+						"02c2d18e033b960562aae3cab37a27ce00d80ccd5ba4b7fe0e7a210245129dbec7780ccc7954725f4168aff2787776e6",
+						"00aa65dae3c8d732d10ecd2c50f8a1baf3001578f71c694e03866e9f3d49ac1e1ce70dd94a733534f106d4cec0eddd16",
+						"1787327b68159716a37440985269cf584bcb1e621d3a7202be6ea05c4cfe244aeb197642555a0645fb87bf7466b2ba48",
+					),
+				},
+				{
+					msg: []byte("abcdef0123456789"),
+					expected: fromHex(-1,
+						"190d119345b94fbd15497bcba94ecf7db2cbfd1e1fe7da034d26cbba169fb3968288b3fafb265f9ebd380512a71c3f2c",
+						"121982811d2491fde9ba7ed31ef9ca474f0e1501297f68c298e9f4c0028add35aea8bb83d53c08cfc007c1e005723cd0",
+						"0bb5e7572275c567462d91807de765611490205a941a5a6af3b1691bfe596c31225d3aabdf15faff860cb4ef17c7c3be",
+						"05571a0f8d3c08d094576981f4a3b8eda0a8e771fcdcc8ecceaf1356a6acf17574518acb506e435b639353c2e14827c8",
+					),
+				},
+				{
+					msg: []byte("q128_" + strings.Repeat("q", 128)),
+					expected: fromHex(-1,
+						"0934aba516a52d8ae479939a91998299c76d39cc0c035cd18813bec433f587e2d7a4fef038260eef0cef4d02aae3eb91",
+						"19a84dd7248a1066f737cc34502ee5555bd3c19f2ecdb3c7d9e24dc65d4e25e50d83f0f77105e955d78f4762d33c17da",
+						"09bcccfa036b4847c9950780733633f13619994394c23ff0b32fa6b795844f4a0673e20282d07bc69641cee04f5e5662",
+						"14f81cd421617428bc3b9fe25afbb751d934a00493524bc4e065635b0555084dd54679df1536101b2c979c0152d09192",
+					),
+				},
+				{
+					msg: []byte("a512_" + strings.Repeat("a", 512)),
+					expected: fromHex(-1,
+						"11fca2ff525572795a801eed17eb12785887c7b63fb77a42be46ce4a34131d71f7a73e95fee3f812aea3de78b4d01569",
+						"01a6ba2f9a11fa5598b2d8ace0fbe0a0eacb65deceb476fbbcb64fd24557c2f4b18ecfc5663e54ae16a84f5ab7f62534",
+						"03a47f8e6d1763ba0cad63d6114c0accbef65707825a511b251a660a9b3994249ae4e63fac38b23da0c398689ee2ab52",
+						"0b6798718c8aed24bc19cb27f866f1c9effcdbf92397ad6448b5c9db90d2b9da6cbabf48adc1adf59a1a28344e79d57e",
+					),
+				},
+			},
+		},
+		{
+			suite: SuiteG2XMDSHA256SSWUNU,
+			hash:  (*G2).EncodeToCurve,
+			vectors: []struct {
+				msg      []byte
+				expected []byte
+			}{
+				{
+					msg: []byte(""),
+					expected: fromHex(-1,
+						"126b855e9e69b1f691f816e48ac6977664d24d99f8724868a184186469ddfd4617367e94527d4b74fc86413483afb35b",
+						"00e7f4568a82b4b7dc1f14c6aaa055edf51502319c723c4dc2688c7fe5944c213f510328082396515734b6612c4e7bb7",
+						"1498aadcf7ae2b345243e281ae076df6de84455d766ab6fcdaad71fab60abb2e8b980a440043cd305db09d283c895e3d",
+						"0caead0fd7b6176c01436833c79d305c78be307da5f6af6c133c47311def6ff1e0babf57a0fb5539fce7ee12407b0a42",
+					),
+				},
+				{
+					msg: []byte("abc"),
+					expected: fromHex(-1,
+						"0296238ea82c6d4adb3c838ee3cb2346049c90b96d602d7bb1b469b905c9228be25c627bffee872def773d5b2a2eb57d",
+						"108ed59fd9fae381abfd1d6bce2fd2fa220990f0f837fa30e0f27914ed6e1454db0d1ee957b219f61da6ff8be0d6441f",
+						"153606c417e59fb331b7ae6bce4fbf7c5190c33ce9402b5ebe2b70e44fca614f3f1382a3625ed5493843d0b0a652fc3f",
+						"033f90f6057aadacae7963b0a0b379dd46750c1c94a6357c99b65f63b79e321ff50fe3053330911c56b6ceea08fee656",
+					),
+				},
+				{
+					msg: []byte("abcdef0123456789"),
+					expected: fromHex(-1,
+						"0da75be60fb6aa0e9e3143e40c42796edf15685cafe0279afd2a67c3dff1c82341f17effd402e4f1af240ea90f4b659b",
+						"038af300ef34c7759a6caaa4e69363cafeed218a1f207e93b2c70d91a1263d375d6730bd6b6509dcac3ba5b567e85bf3",
+						"0492f4fed741b073e5a82580f7c663f9b79e036b70ab3e51162359cec4e77c78086fe879b65ca7a47d34374c8315ac5e",
+						"19b148cbdf163cf0894f29660d2e7bfb2b68e37d54cc83fd4e6e62c020eaa48709302ef8e746736c0e19342cc1ce3df4",
+					),
+				},
+				{
+					msg: []byte("q128_" + strings.Repeat("q", 128)),
+					expected: fromHex(-1,
+						"12c8c05c1d5fc7bfa847f4d7d81e294e66b9a78bc9953990c358945e1f042eedafce608b67fdd3ab0cb2e6e263b9b1ad",
+						"0c5ae723be00e6c3f0efe184fdc0702b64588fe77dda152ab13099a3bacd3876767fa7bbad6d6fd90b3642e902b208f9",
+						"11c624c56dbe154d759d021eec60fab3d8b852395a89de497e48504366feedd4662d023af447d66926a28076813dd646",
+						"04e77ddb3ede41b5ec4396b7421dd916efc68a358a0d7425bddd253547f2fb4830522358491827265dfc5bcc1928a569",
+					),
+				},
+				{
+					msg: []byte("a512_" + strings.Repeat("a", 512)),
+					expected: fromHex(-1,
+						"1565c2f625032d232f13121d3cfb476f45275c303a037faa255f9da62000c2c864ea881e2bcddd111edc4a3c0da3e88d",
+						"0ea4e7c33d43e17cc516a72f76437c4bf81d8f4eac69ac355d3bf9b71b8138d55dc10fd458be115afa798b55dac34be1",
+						"0f8991d2a1ad662e7b6f58ab787947f1fa607fce12dde171bc17903b012091b657e15333e11701edcf5b63ba2a561247",
+						"043b6f5fe4e52c839148dc66f2b3751e69a0f6ebb3d056d6465d50d4108543ecd956e10fa1640dfd9bc0030cc2558d28",
+					),
+				},
+			},
+		},
+	} {
+		domain := []byte("QUUX-V01-CS02-with-" + suite.suite)
+		g := NewG2()
+		for i, v := range suite.vectors {
+			p0, err := suite.hash(g, v.msg, domain)
+			if err != nil {
+				t.Fatal("hash to point fails", suite.suite, i, err)
+			}
+			if !bytes.Equal(g.ToBytes(p0), v.expected) {
+				t.Fatal("hash to point fails", suite.suite, i)
+			}
+		}
+	}
+}
+
+func TestG2HashToCurveXOF(t *testing.T) {
+	g := NewG2()
+	msg := []byte("abc")
+	for i, v := range []struct {
+		expand Expander
+		hash   func(msg, domain []byte) (*PointG2, error)
+		encode func(msg, domain []byte) (*PointG2, error)
+		suites [2]string
+	}{
+		{expandMsgSHAKE128XOF, g.HashToCurveSHAKE128, g.EncodeToCurveSHAKE128, [2]string{SuiteG2XOFSHAKE128SSWURO, SuiteG2XOFSHAKE128SSWUNU}},
+		{expandMsgSHAKE256XOF, g.HashToCurveSHAKE256, g.EncodeToCurveSHAKE256, [2]string{SuiteG2XOFSHAKE256SSWURO, SuiteG2XOFSHAKE256SSWUNU}},
+	} {
+		// hash_to_curve(msg) = map_to_curve(u0) + map_to_curve(u1)
+		// since both isogeny map and cofactor clearing are homomorphisms
+		domain := []byte("QUUX-V01-CS02-with-" + v.suites[0])
+		p, err := v.hash(msg, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := hashToFp(v.expand, msg, domain, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q0, _ := g.MapToCurve(g.f.toBytes(&fe2{*u[0], *u[1]}))
+		q1, _ := g.MapToCurve(g.f.toBytes(&fe2{*u[2], *u[3]}))
+		g.Add(q0, q0, q1)
+		if !g.Equal(p, q0) || !g.IsOnCurve(p) || !g.InCorrectSubgroup(p) {
+			t.Fatal("bad hash to curve", i)
+		}
+		domain = []byte("QUUX-V01-CS02-with-" + v.suites[1])
+		p, err = v.encode(msg, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, _ = hashToFp(v.expand, msg, domain, 2)
+		q0, _ = g.MapToCurve(g.f.toBytes(&fe2{*u[0], *u[1]}))
+		if !g.Equal(p, q0) {
+			t.Fatal("bad encode to curve", i)
+		}
+		// must differ from xmd suite with the same tag
+		q0, _ = g.EncodeToCurve(msg, domain)
+		if g.Equal(p, q0) {
+			t.Fatal("xof and xmd suites are expected to differ", i)
+		}
+	}
+}
+
+func TestG2HashToCurveWith(t *testing.T) {
+	g := NewG2()
+	msg, domain := []byte("abc"), []byte("QUUX-V01-CS02-with-"+SuiteG2XMDSHA256SSWURO)
+	p0, err := g.HashToCurveWith(sha256.New, msg, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p1, _ := g.HashToCurve(msg, domain)
+	if !g.Equal(p0, p1) {
+		t.Fatal("bad hash to curve with sha256")
+	}
+	p0, err = g.EncodeToCurveWith(sha256.New, msg, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p1, _ = g.EncodeToCurve(msg, domain)
+	if !g.Equal(p0, p1) {
+		t.Fatal("bad encode to curve with sha256")
+	}
+	p0, err = g.HashToCurveWith(sha512.New, msg, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.IsOnCurve(p0) || !g.InCorrectSubgroup(p0) {
+		t.Fatal("bad hash to curve with sha512")
+	}
+	p1, _ = g.HashToCurve(msg, domain)
+	if g.Equal(p0, p1) {
+		t.Fatal("hash functions are expected to give different points")
+	}
+	if _, err := g.HashToCurveWith(sha1.New, msg, domain); err == nil {
+		t.Fatal("short hash output is expected to fail")
+	}
+}
+
+func TestG2Hasher(t *testing.T) {
+	g := NewG2()
+	msg := make([]byte, 10000)
+	_, _ = rand.Read(msg)
+	// long tag is hashed before the message is written
+	longDomain := []byte(strings.Repeat("a", 300))
+	for _, id := range []string{SuiteG2XMDSHA256SSWURO, SuiteG2XMDSHA256SSWUNU, SuiteG2XOFSHAKE128SSWUNU, SuiteG2XOFSHAKE256SSWURO, "BLS12381G2_XMD:SHA-512_SSWU_NU_"} {
+		s, err := NewSuite(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, domain := range [][]byte{s.DST("QUUX", 1, 2), longDomain} {
+			expected, err := g.HashToCurveWithSuite(s, msg, domain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h, err := g.NewHasher(s, domain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for j := 0; j < 2; j++ {
+				for rest := msg; len(rest) > 0; {
+					n := mrand.Intn(1000)
+					if n > len(rest) {
+						n = len(rest)
+					}
+					if _, err := h.Write(rest[:n]); err != nil {
+						t.Fatal(err)
+					}
+					rest = rest[n:]
+				}
+				p, err := h.Sum()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !g.Equal(p, expected) {
+					t.Fatalf("bad streaming hash with suite %s", id)
+				}
+				if _, err := h.Write(msg); err == nil {
+					t.Fatal("write after sum is expected to fail")
+				}
+				if _, err := h.Sum(); err == nil {
+					t.Fatal("sum after sum is expected to fail")
+				}
+				h.Reset()
+			}
+		}
+	}
+	// default suite is the suite of HashToCurve
+	domain := []byte("QUUX-V01-CS02-with-" + SuiteG2XMDSHA256SSWURO)
+	expected, err := g.HashToCurve(msg, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := g.NewHasher(nil, domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = h.Write(msg)
+	p, err := h.Sum()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Equal(p, expected) {
+		t.Fatal("bad streaming hash with default suite")
+	}
+	s, _ := NewSuite(SuiteG1XMDSHA256SSWURO)
+	if _, err := g.NewHasher(s, domain); err == nil {
+		t.Fatal("suite of other group is expected to be rejected")
+	}
+}
+
+func TestG2HashToCurveBatch(t *testing.T) {
+	g := NewG2()
+	domain := []byte("QUUX-V01-CS02-with-" + SuiteG2XMDSHA256SSWURO)
+	for _, n := range []int{0, 1, 2, 37} {
+		msgs := make([][]byte, n)
+		for i := range msgs {
+			msgs[i] = make([]byte, i)
+			_, _ = rand.Read(msgs[i])
+		}
+		points, err := g.HashToCurveBatch(msgs, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(points) != n {
+			t.Fatal("bad number of points")
+		}
+		for i := range msgs {
+			expected, err := g.HashToCurve(msgs[i], domain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !g.Equal(points[i], expected) || !g.IsAffine(points[i]) {
+				t.Fatalf("bad batch hash to curve at %d of %d", i, n)
+			}
+		}
+	}
+}
+
+func TestG2HashToCurveBatchDegenerate(t *testing.T) {
+	g := NewG2()
+	f := g.f
+	// messages are taken as the expanded bytes, so that field elements can be chosen
+	expand := func(msg, _ []byte, _ int) ([]byte, error) { return msg, nil }
+	encode := func(u0, u1 *fe2) []byte {
+		msg := make([]byte, 256)
+		for i, e := range []*fe{&u0[0], &u0[1], &u1[0], &u1[1]} {
+			toBig(e).FillBytes(msg[64*i : 64*(i+1)])
+		}
+		return msg
+	}
+	var msgs [][]byte
+	for i := 0; i < fuz; i++ {
+		u, _ := f.rand(rand.Reader)
+		v := f.new()
+		f.neg(v, u)
+		msgs = append(msgs,
+			// sum on the isogenous curve is a doubling
+			encode(u, u),
+			// sum is infinity as f(-u) = -f(u)
+			encode(u, v),
+		)
+	}
+	points := make([]*PointG2, len(msgs))
+	if err := g.hashToCurveBatch(points, expand, msgs, nil); err != nil {
+		t.Fatal(err)
+	}
+	for i := range msgs {
+		expected, err := g.hashToCurve(expand, msgs[i], nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g.Equal(points[i], expected) || !g.IsOnCurve(points[i]) {
+			t.Fatalf("bad batch hash to curve at %d", i)
+		}
+	}
+}
+
+func TestG2SWUMapConstantTime(t *testing.T) {
+	g := NewG2()
+	e := newFp2()
+	inputs := []*fe2{e.zero(), e.one(), e.new()}
+	e.neg(inputs[2], swuParamsForG2.z)
+	for i := 0; i < fuz; i++ {
+		u, _ := e.rand(rand.Reader)
+		inputs = append(inputs, u)
+		// elements with a zero coefficient exercise sign of the extension element
+		v := e.new()
+		v[1].set(&u[1])
+		inputs = append(inputs, v)
+	}
+	for _, u := range inputs {
+		x0, y0 := swuMapG2(e, u)
+		x1, y1 := swuMapG2CT(e, u)
+		if !e.equal(x0, x1) || !e.equal(y0, y1) {
+			t.Fatalf("constant time map doesn't match the reference for %s %s", u[0], u[1])
+		}
+		isogenyMapG2(e, x1, y1)
+		if !g.IsOnCurve(&PointG2{*x1, *y1, *e.one()}) {
+			t.Fatal("mapped point is not on curve")
+		}
+	}
+}
+
+func TestG2ConstantTimeArithmetic(t *testing.T) {
+	g := NewG2()
+	f := g.f
+	// homogeneous projective form of a point given in Jacobian coordinates is (X * Z : Y : Z^3)
+	projective := func(p *PointG2) *PointG2 {
+		r := g.New().Set(p)
+		f.mul(&r[0], &p[0], &p[2])
+		f.square(&r[2], &p[2])
+		f.mul(&r[2], &r[2], &p[2])
+		return r
+	}
+	for i := 0; i < fuz; i++ {
+		a, b := g.rand(), g.rand()
+		cases := [][3]*PointG2{
+			{a, b, g.Add(g.New(), a, b)},
+			{a, a, g.Double(g.New(), a)},
+			{a, g.Neg(g.New(), a), g.Zero()},
+			{a, g.Zero(), a},
+			{g.Zero(), b, b},
+			{g.Zero(), g.Zero(), g.Zero()},
+		}
+		for j, c := range cases {
+			r := g.addCT(g.New(), projective(c[0]), projective(c[1]))
+			if !g.Equal(g.affineCT(r), c[2]) {
+				t.Fatalf("bad constant time addition at case %d", j)
+			}
+			r = g.doubleCT(g.New(), projective(c[0]))
+			if !g.Equal(g.affineCT(r), g.Double(g.New(), c[0])) {
+				t.Fatalf("bad constant time doubling at case %d", j)
+			}
+		}
+		r := g.affineCT(projective(a))
+		if !g.IsAffine(r) || !g.Equal(r, g.Affine(g.New().Set(a))) {
+			t.Fatal("bad constant time affine conversion")
+		}
+		r = g.affineCT(g.Zero())
+		if !g.IsZero(r) || !f.equal(&r[1], f.one()) {
+			t.Fatal("bad constant time affine conversion of infinity")
+		}
+		u, _ := f.rand(rand.Reader)
+		x, y := swuMapG2CT(f, u)
+		p := g.New()
+		isogenyMapG2Projective(f, p, x, y)
+		isogenyMapG2(f, x, y)
+		expected := &PointG2{*x, *y, *f.one()}
+		if !g.Equal(g.affineCT(g.New().Set(p)), expected) {
+			t.Fatal("projective isogeny map doesn't match the reference")
+		}
+		g.clearCofactorCT(p)
+		g.ClearCofactor(expected)
+		if !g.Equal(g.affineCT(p), expected) {
+			t.Fatal("constant time cofactor clearing doesn't match the reference")
+		}
+	}
+}
+
 func BenchmarkG2Add(t *testing.B) {
 	g2 := NewG2()
 	a, b, c := g2.rand(), g2.rand(), PointG2{}
@@ -426,6 +957,32 @@ func BenchmarkG2Mul(t *testing.B) {
 	}
 }
 
+func BenchmarkG2SubgroupCheck(t *testing.B) {
+	g := NewG2()
+	a := g.rand()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		_ = g.InCorrectSubgroup(a)
+	}
+}
+
+func BenchmarkG2AffineBatch(t *testing.B) {
+	g := NewG2()
+	n := 64
+	points, copies := make([]*PointG2, n), make([]*PointG2, n)
+	for i := 0; i < n; i++ {
+		points[i] = g.rand()
+		copies[i] = g.New()
+	}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		for j := 0; j < n; j++ {
+			copies[j].Set(points[j])
+		}
+		g.AffineBatch(copies)
+	}
+}
+
 func BenchmarkG2SWUMap(t *testing.B) {
 	a := fromHex(96, "0x1234")
 	g2 := NewG2()
@@ -437,3 +994,44 @@ func BenchmarkG2SWUMap(t *testing.B) {
 		}
 	}
 }
+
+func BenchmarkG2SWUMapConstantTime(t *testing.B) {
+	e := newFp2()
+	u, _ := e.rand(rand.Reader)
+	t.Run("Reference", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			swuMapG2(e, u)
+		}
+	})
+	t.Run("ConstantTime", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			swuMapG2CT(e, u)
+		}
+	})
+}
+
+func BenchmarkG2HashToCurveBatch(t *testing.B) {
+	g := NewG2()
+	domain := []byte("QUUX-V01-CS02-with-" + SuiteG2XMDSHA256SSWURO)
+	msgs := make([][]byte, 128)
+	for i := range msgs {
+		msgs[i] = make([]byte, 32)
+		_, _ = rand.Read(msgs[i])
+	}
+	t.Run("Serial", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			for j := range msgs {
+				if _, err := g.HashToCurve(msgs[j], domain); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	})
+	t.Run("Batch", func(t *testing.B) {
+		for i := 0; i < t.N; i++ {
+			if _, err := g.HashToCurveBatch(msgs, domain); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
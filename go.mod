@@ -3,6 +3,7 @@ module github.com/kilic/bls12-381
 go 1.12
 
 require (
-	golang.org/x/sys v0.0.0-20191025090151-53bf42e6b339
+	golang.org/x/crypto v0.0.0-20211117183948-ae814b36b871
+	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1
 	gopkg.in/yaml.v2 v2.2.4
 )
@@ -37,16 +37,79 @@ func (g *GT) FromBytes(in []byte) (*E, error) {
 	return e, nil
 }
 
+// FromCompressed expects 288 byte input in torus form and returns target group element.
+// FromCompressed returns error if given element is not on correct subgroup.
+func (g *GT) FromCompressed(in []byte) (*E, error) {
+	if len(in) != 288 {
+		return nil, fmt.Errorf("input string should be equal to 288 bytes")
+	}
+	fp6 := g.fp12.fp6
+	c, err := fp6.fromBytes(in)
+	if err != nil {
+		return nil, err
+	}
+	if fp6.isZero(c) {
+		return g.One(), nil
+	}
+	e := g.New()
+	g.fp12.torusDecompress(e, c)
+	if !g.IsValid(e) {
+		return e, fmt.Errorf("invalid element")
+	}
+	return e, nil
+}
+
+// ToCompressed serializes target group element in 288 bytes.
+// Element a0 + a1 * w is encoded as (1 + a0) / a1 which is enough
+// to recover elements of cyclotomic subgroup. One is encoded as zero.
+// Input is expected to be a valid target group element.
+func (g *GT) ToCompressed(e *E) []byte {
+	fp6 := g.fp12.fp6
+	c := fp6.zero()
+	g.fp12.torusCompress(c, e)
+	return fp6.toBytes(c)
+}
+
 // ToBytes serializes target group element.
 func (g *GT) ToBytes(e *E) []byte {
 	return g.fp12.toBytes(e)
 }
 
 // IsValid checks whether given target group element is in correct subgroup.
+// Element is expected to be in cyclotomic subgroup and then
+// it is in order q subgroup if and only if e^p == e^x where x is the curve parameter.
+// https://eprint.iacr.org/2021/1130
 func (g *GT) IsValid(e *E) bool {
-	r := g.New()
-	g.fp12.exp(r, e, q)
-	return g.Equal(r, g.fp12.one())
+	if !g.IsInCyclotomicSubgroup(e) {
+		return false
+	}
+	t0, t1 := g.New(), g.New()
+	g.fp12.frobeniusMap(t0, e, 1)
+	// x is negative
+	g.fp12.cyclotomicExp(t1, e, x)
+	g.fp12.conjugate(t1, t1)
+	return g.Equal(t0, t1)
+}
+
+// IsInCyclotomicSubgroup checks whether given element is in cyclotomic subgroup
+// that is whether e^(p^6 + 1) == 1 and e^(p^4 - p^2 + 1) == 1.
+// Elements of the target group are in this subgroup hence it is a cheap way to reject malformed inputs.
+func (g *GT) IsInCyclotomicSubgroup(e *E) bool {
+	if g.fp12.isZero(e) {
+		return false
+	}
+	t0, t1 := g.New(), g.New()
+	// e^(p^6) == e^-1
+	g.fp12.conjugate(t0, e)
+	g.fp12.mul(t0, t0, e)
+	if !g.IsOne(t0) {
+		return false
+	}
+	// e^(p^4) * e == e^(p^2)
+	g.fp12.frobeniusMap(t0, e, 4)
+	g.fp12.mul(t0, t0, e)
+	g.fp12.frobeniusMap(t1, e, 2)
+	return g.Equal(t0, t1)
 }
 
 // New initializes a new target group element which is equal to one
@@ -95,11 +158,166 @@ func (g *GT) Square(c, a *E) {
 }
 
 // Exp exponents an element `a` by a scalar `s` and assigns the result to the element in first argument.
+// Any element is accepted, such as Miller loop outputs. Elements of cyclotomic subgroup are exponentiated
+// with faster squarings. For target group elements ExpInGroup is faster.
 func (g *GT) Exp(c, a *E, s *big.Int) {
-	g.fp12.cyclotomicExp(c, a, s)
+	if g.IsInCyclotomicSubgroup(a) {
+		g.fp12.cyclotomicExp(c, a, s)
+		return
+	}
+	g.fp12.exp(c, a, s)
+}
+
+// ExpInGroup exponents an element `a` of target group by a scalar `s` and assigns the result to the element in first argument.
+// Scalar is reduced modulo group order and result is not correct for elements that are not in target group.
+func (g *GT) ExpInGroup(c, a *E, s *big.Int) {
+	g.fp12.gtExp(c, a, s)
 }
 
 // Inverse inverses an element `a` and assigns the result to the element in first argument.
 func (g *GT) Inverse(c, a *E) {
 	g.fp12.inverse(c, a)
 }
+
+// InverseUnitary inverses an element `a` of cyclotomic subgroup, such as target group elements,
+// with conjugation and assigns the result to the element in first argument.
+// Result is not correct for elements that are not in cyclotomic subgroup.
+func (g *GT) InverseUnitary(c, a *E) {
+	g.fp12.conjugate(c, a)
+}
+
+// MultiExp calculates multi exponentiation. Given pairs of target group element and scalar values
+// (e_0, s_0), (e_1, s_1), ... (e_n, s_n) calculates r = e_0^s_0 * e_1^s_1 * ... * e_n^s_n.
+// Elements are expected to be in target group. Each scalar is decomposed into four 64 bit
+// digits with Frobenius map as in ExpInGroup. Exponentiations are evaluated jointly for small inputs
+// and bucket method is applied on decomposed pairs for larger ones.
+func (g *GT) MultiExp(r *E, elems []*E, powers []*big.Int) (*E, error) {
+	if len(elems) != len(powers) {
+		return nil, fmt.Errorf("element and scalar vectors should be in same length")
+	}
+	fp12 := g.fp12
+	n := 4 * len(elems)
+	// window size that minimizes number of multiplications of bucket method
+	c, cost := uint(1), 0
+	for w := uint(1); w <= 16; w++ {
+		if k := ((64 + int(w) - 1) / int(w)) * (n + (2 << w)); w == 1 || k < cost {
+			c, cost = w, k
+		}
+	}
+	// joint exponentiation costs table construction and a multiplication per bit for each element
+	if 71*len(elems) <= cost {
+		fp12.gtExpJoint(r, elems, powers)
+		return r, nil
+	}
+	bases := make([]fe12, n)
+	digits := make([]uint64, n)
+	for i := range elems {
+		fp12.gtBases(&bases[4*i], &bases[4*i+1], &bases[4*i+2], &bases[4*i+3], elems[i])
+		d := gtDecompose(powers[i])
+		copy(digits[4*i:], d[:])
+	}
+	mask := uint64(1)<<c - 1
+	bucket := make([]fe12, mask)
+	used := make([]bool, mask)
+	acc, sum := g.New(), g.New()
+	res := g.New()
+	for w := int((64+c-1)/c) - 1; w >= 0; w-- {
+		for j := uint(0); j < c; j++ {
+			fp12.cyclotomicSquare(res, res)
+		}
+		for i := range used {
+			used[i] = false
+		}
+		for i := 0; i < n; i++ {
+			index := (digits[i] >> (uint(w) * c)) & mask
+			if index == 0 {
+				continue
+			}
+			if used[index-1] {
+				fp12.mul(&bucket[index-1], &bucket[index-1], &bases[i])
+			} else {
+				fp12.copy(&bucket[index-1], &bases[i])
+				used[index-1] = true
+			}
+		}
+		// acc = bucket[0] * bucket[1]^2 * ... * bucket[m-1]^m with running products
+		started := false
+		for i := len(bucket) - 1; i >= 0; i-- {
+			if !started {
+				if !used[i] {
+					continue
+				}
+				g.Copy(sum, &bucket[i])
+				g.Copy(acc, sum)
+				started = true
+				continue
+			}
+			if used[i] {
+				fp12.mul(sum, sum, &bucket[i])
+			}
+			fp12.mul(acc, acc, sum)
+		}
+		if started {
+			fp12.mul(res, res, acc)
+		}
+	}
+	g.Copy(r, res)
+	return r, nil
+}
+
+// gtFixedBaseWindow is the window size in bits of precomputed table of a fixed base.
+const gtFixedBaseWindow = 6
+
+// PreparedGT keeps precomputed powers of a fixed target group element
+// for faster repeated exponentiation.
+type PreparedGT struct {
+	// table[i][d-1] = a^(d * 2^(6 * i))
+	table [(64 + gtFixedBaseWindow - 1) / gtFixedBaseWindow][1<<gtFixedBaseWindow - 1]fe12
+}
+
+// PrepareBase precomputes powers of given target group element to be used with ExpPrepared.
+// Precomputed table takes about 400 KB. Element is expected to be in target group as in ExpInGroup.
+func (g *GT) PrepareBase(a *E) *PreparedGT {
+	fp12 := g.fp12
+	p := new(PreparedGT)
+	base := g.New()
+	g.Copy(base, a)
+	for i := range p.table {
+		t := &p.table[i]
+		fp12.copy(&t[0], base)
+		for d := 1; d < len(t); d++ {
+			fp12.mul(&t[d], &t[d-1], base)
+		}
+		for j := 0; j < gtFixedBaseWindow; j++ {
+			fp12.cyclotomicSquare(base, base)
+		}
+	}
+	return p
+}
+
+// ExpPrepared exponentiates prepared element by a scalar `s` and assigns the result to the element in first argument.
+// Scalar is reduced modulo group order.
+func (g *GT) ExpPrepared(c *E, p *PreparedGT, s *big.Int) {
+	fp12 := g.fp12
+	digits := gtDecompose(s)
+	var parts [4]fe12
+	mask := uint64(1)<<gtFixedBaseWindow - 1
+	for k := 0; k < 4; k++ {
+		parts[k] = *g.One()
+		for i := range p.table {
+			if d := (digits[k] >> (uint(i) * gtFixedBaseWindow)) & mask; d != 0 {
+				fp12.mul(&parts[k], &parts[k], &p.table[i][d-1])
+			}
+		}
+	}
+	// a^(s_1 * |x|) = (a^s_1)^-p and a^(s_3 * |x|^3) = (a^s_3)^(-p^3)
+	t := g.New()
+	fp12.frobeniusMap(t, &parts[1], 1)
+	fp12.conjugate(t, t)
+	fp12.mul(&parts[0], &parts[0], t)
+	fp12.frobeniusMap(t, &parts[2], 2)
+	fp12.mul(&parts[0], &parts[0], t)
+	fp12.frobeniusMap(t, &parts[3], 3)
+	fp12.conjugate(t, t)
+	fp12.mul(c, &parts[0], t)
+}
@@ -3,10 +3,62 @@ package bls12381
 import (
 	"crypto/sha256"
 	"fmt"
+	"hash"
+	"io"
+	"math/big"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Suite identifiers of hashing to curve for BLS12-381 as defined in RFC 9380.
+// Applications are expected to build their domain separation tags from these identifiers.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-8.8
+const (
+	// SuiteG1XMDSHA256SSWURO is the suite of G1.HashToCurve.
+	SuiteG1XMDSHA256SSWURO = "BLS12381G1_XMD:SHA-256_SSWU_RO_"
+	// SuiteG1XMDSHA256SSWUNU is the suite of G1.EncodeToCurve.
+	SuiteG1XMDSHA256SSWUNU = "BLS12381G1_XMD:SHA-256_SSWU_NU_"
+	// SuiteG2XMDSHA256SSWURO is the suite of G2.HashToCurve.
+	SuiteG2XMDSHA256SSWURO = "BLS12381G2_XMD:SHA-256_SSWU_RO_"
+	// SuiteG2XMDSHA256SSWUNU is the suite of G2.EncodeToCurve.
+	SuiteG2XMDSHA256SSWUNU = "BLS12381G2_XMD:SHA-256_SSWU_NU_"
 )
 
-func hashToFpXMDSHA256(msg []byte, domain []byte, count int) ([]*fe, error) {
-	randBytes, err := expandMsgSHA256XMD(msg, domain, count*64)
+// Suite identifiers of hashing to curve with expand_message_xof.
+// RFC 9380 does not define these suites for BLS12-381, identifiers are
+// built with the naming convention of the RFC.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-8.10
+const (
+	// SuiteG1XOFSHAKE128SSWURO is the suite of G1.HashToCurveSHAKE128.
+	SuiteG1XOFSHAKE128SSWURO = "BLS12381G1_XOF:SHAKE-128_SSWU_RO_"
+	// SuiteG1XOFSHAKE128SSWUNU is the suite of G1.EncodeToCurveSHAKE128.
+	SuiteG1XOFSHAKE128SSWUNU = "BLS12381G1_XOF:SHAKE-128_SSWU_NU_"
+	// SuiteG1XOFSHAKE256SSWURO is the suite of G1.HashToCurveSHAKE256.
+	SuiteG1XOFSHAKE256SSWURO = "BLS12381G1_XOF:SHAKE-256_SSWU_RO_"
+	// SuiteG1XOFSHAKE256SSWUNU is the suite of G1.EncodeToCurveSHAKE256.
+	SuiteG1XOFSHAKE256SSWUNU = "BLS12381G1_XOF:SHAKE-256_SSWU_NU_"
+	// SuiteG2XOFSHAKE128SSWURO is the suite of G2.HashToCurveSHAKE128.
+	SuiteG2XOFSHAKE128SSWURO = "BLS12381G2_XOF:SHAKE-128_SSWU_RO_"
+	// SuiteG2XOFSHAKE128SSWUNU is the suite of G2.EncodeToCurveSHAKE128.
+	SuiteG2XOFSHAKE128SSWUNU = "BLS12381G2_XOF:SHAKE-128_SSWU_NU_"
+	// SuiteG2XOFSHAKE256SSWURO is the suite of G2.HashToCurveSHAKE256.
+	SuiteG2XOFSHAKE256SSWURO = "BLS12381G2_XOF:SHAKE-256_SSWU_RO_"
+	// SuiteG2XOFSHAKE256SSWUNU is the suite of G2.EncodeToCurveSHAKE256.
+	SuiteG2XOFSHAKE256SSWUNU = "BLS12381G2_XOF:SHAKE-256_SSWU_NU_"
+)
+
+// Expander is a message expansion function of RFC 9380 which
+// returns outLen uniformly random bytes given message and domain separation tag.
+type Expander func(msg []byte, domain []byte, outLen int) ([]byte, error)
+
+// hashToFp implements hash_to_field of RFC 9380 with given message expansion
+// where each element is obtained from L = 64 bytes.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.2
+func hashToFp(expand Expander, msg []byte, domain []byte, count int) ([]*fe, error) {
+	if count == 0 {
+		return []*fe{}, nil
+	}
+	randBytes, err := expand(msg, domain, count*64)
 	if err != nil {
 		return nil, err
 	}
@@ -20,16 +72,161 @@ func hashToFpXMDSHA256(msg []byte, domain []byte, count int) ([]*fe, error) {
 	return els, nil
 }
 
+// HashToFieldFp implements hash_to_field of RFC 9380 for base field and returns count elements
+// in 48 byte big endian encoding which is also the input format of G1.MapToCurve.
+// Zero count gives an empty slice and negative count is rejected.
+// If expander is nil expand_message_xmd with SHA-256 is used.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.2
+func HashToFieldFp(msg, domain []byte, count int, expand Expander) ([][]byte, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count should not be negative")
+	}
+	if expand == nil {
+		expand = expandMsgSHA256XMD
+	}
+	els, err := hashToFp(expand, msg, domain, count)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]byte, count)
+	for i := range els {
+		out[i] = toBytes(els[i])
+	}
+	return out, nil
+}
+
+// HashToFieldFp2 implements hash_to_field of RFC 9380 for quadratic extension field and returns count elements
+// in 96 byte c1 || c0 encoding which is also the input format of G2.MapToCurve.
+// Zero count gives an empty slice and negative count is rejected.
+// If expander is nil expand_message_xmd with SHA-256 is used.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.2
+func HashToFieldFp2(msg, domain []byte, count int, expand Expander) ([][]byte, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count should not be negative")
+	}
+	if expand == nil {
+		expand = expandMsgSHA256XMD
+	}
+	els, err := hashToFp(expand, msg, domain, 2*count)
+	if err != nil {
+		return nil, err
+	}
+	fp2 := newFp2()
+	out := make([][]byte, count)
+	for i := range out {
+		out[i] = fp2.toBytes(&fe2{*els[2*i], *els[2*i+1]})
+	}
+	return out, nil
+}
+
+// HashToFieldFr implements hash_to_field of RFC 9380 for scalar field and returns count scalars
+// which are reduced modulo group order. Each scalar is obtained from L = 48 bytes
+// that is ceil((ceil(log2(r)) + k) / 8) for 128 bits of security.
+// Zero count gives an empty slice and negative count is rejected.
+// If expander is nil expand_message_xmd with SHA-256 is used.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.2
+func HashToFieldFr(msg, domain []byte, count int, expand Expander) ([]*big.Int, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count should not be negative")
+	}
+	if count == 0 {
+		return []*big.Int{}, nil
+	}
+	if expand == nil {
+		expand = expandMsgSHA256XMD
+	}
+	const L = 48
+	randBytes, err := expand(msg, domain, count*L)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*big.Int, count)
+	for i := range out {
+		out[i] = new(big.Int).SetBytes(randBytes[i*L : (i+1)*L])
+		out[i].Mod(out[i], q)
+	}
+	return out, nil
+}
+
+// expandMsgSHA256XMD implements expand_message_xmd of RFC 9380 with SHA-256.
 func expandMsgSHA256XMD(msg []byte, domain []byte, outLen int) ([]byte, error) {
-	h := sha256.New()
+	return expandMsgXMD(sha256.New, msg, domain, outLen)
+}
+
+// ExpandMsgXMD returns expand_message_xmd with given hash function such as sha256.New or sha512.New.
+func ExpandMsgXMD(newHash func() hash.Hash) Expander {
+	return func(msg []byte, domain []byte, outLen int) ([]byte, error) {
+		return expandMsgXMD(newHash, msg, domain, outLen)
+	}
+}
+
+// expandMsgXMD implements expand_message_xmd of RFC 9380 with a Merkle-Damgard or a similar hash function.
+// Z_pad is as long as the block size and b_in_bytes is the output size of the hash function
+// which is expected to be at least 32 bytes for 128 bits of security.
+// Domain separation tags longer than 255 bytes are hashed as the RFC requires.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.3.1
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.3.3
+func expandMsgXMD(newHash func() hash.Hash, msg []byte, domain []byte, outLen int) ([]byte, error) {
+	s, err := newXMDStream(newHash, domain)
+	if err != nil {
+		return nil, err
+	}
+	_, _ = s.Write(msg)
+	return s.expand(outLen)
+}
+
+// messageStream is the incremental form of a message expansion where
+// the message is written in pieces before it is expanded.
+// A stream must be reset after expansion to be used again.
+type messageStream interface {
+	io.Writer
+	expand(outLen int) ([]byte, error)
+	reset()
+}
+
+// xmdStream is expand_message_xmd that accepts message incrementally.
+// Message appears only in b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
+// so it is written to the hash function as it arrives.
+type xmdStream struct {
+	h      hash.Hash
+	domain []byte
+}
+
+func newXMDStream(newHash func() hash.Hash, domain []byte) (*xmdStream, error) {
+	h := newHash()
+	if h.Size() < 32 {
+		return nil, fmt.Errorf("hash output is too short")
+	}
+	if len(domain) > 255 {
+		// DST = H("H2C-OVERSIZE-DST-" || a_very_long_DST)
+		_, _ = h.Write([]byte("H2C-OVERSIZE-DST-"))
+		_, _ = h.Write(domain)
+		domain = h.Sum(nil)
+	}
+	s := &xmdStream{h, domain}
+	s.reset()
+	return s, nil
+}
+
+func (s *xmdStream) reset() {
+	s.h.Reset()
+	_, _ = s.h.Write(make([]byte, s.h.BlockSize()))
+}
+
+func (s *xmdStream) Write(msg []byte) (int, error) {
+	return s.h.Write(msg)
+}
+
+func (s *xmdStream) expand(outLen int) ([]byte, error) {
+	h, domain := s.h, s.domain
 	domainLen := uint8(len(domain))
-	if domainLen > 255 {
-		return nil, fmt.Errorf("invalid domain length")
+	// ell = ceil(len_in_bytes / b_in_bytes)
+	ell := (outLen + h.Size() - 1) / h.Size()
+	if outLen <= 0 || outLen > 65535 || ell > 255 {
+		return nil, fmt.Errorf("invalid output length")
 	}
 	// DST_prime = DST || I2OSP(len(DST), 1)
 	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
-	_, _ = h.Write(make([]byte, h.BlockSize()))
-	_, _ = h.Write(msg)
 	_, _ = h.Write([]byte{uint8(outLen >> 8), uint8(outLen)})
 	_, _ = h.Write([]byte{0})
 	_, _ = h.Write(domain)
@@ -44,8 +241,6 @@ func expandMsgSHA256XMD(msg []byte, domain []byte, outLen int) ([]byte, error) {
 	_, _ = h.Write([]byte{domainLen})
 	b1 := h.Sum(nil)
 
-	// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
-	ell := (outLen + h.Size() - 1) / h.Size()
 	bi := b1
 	out := make([]byte, outLen)
 	for i := 1; i < ell; i++ {
@@ -68,3 +263,72 @@ func expandMsgSHA256XMD(msg []byte, domain []byte, outLen int) ([]byte, error) {
 	copy(out[(ell-1)*h.Size():], bi[:])
 	return out[:outLen], nil
 }
+
+// ExpandMsgXOF returns expand_message_xof with given extendable output function
+// such as sha3.NewShake128 where k is its security level in bits.
+func ExpandMsgXOF(newHash func() sha3.ShakeHash, k int) Expander {
+	return func(msg []byte, domain []byte, outLen int) ([]byte, error) {
+		return expandMsgXOF(newHash, k, msg, domain, outLen)
+	}
+}
+
+// expandMsgSHAKE128XOF implements expand_message_xof of RFC 9380 with SHAKE128.
+func expandMsgSHAKE128XOF(msg []byte, domain []byte, outLen int) ([]byte, error) {
+	return expandMsgXOF(sha3.NewShake128, 128, msg, domain, outLen)
+}
+
+// expandMsgSHAKE256XOF implements expand_message_xof of RFC 9380 with SHAKE256.
+func expandMsgSHAKE256XOF(msg []byte, domain []byte, outLen int) ([]byte, error) {
+	return expandMsgXOF(sha3.NewShake256, 256, msg, domain, outLen)
+}
+
+// expandMsgXOF implements expand_message_xof of RFC 9380 where k is the security level of the hash function.
+// Domain separation tags longer than 255 bytes are hashed as the RFC requires.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.3.2
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-5.3.3
+func expandMsgXOF(newHash func() sha3.ShakeHash, k int, msg []byte, domain []byte, outLen int) ([]byte, error) {
+	s := newXOFStream(newHash, k, domain)
+	_, _ = s.Write(msg)
+	return s.expand(outLen)
+}
+
+// xofStream is expand_message_xof that accepts message incrementally.
+type xofStream struct {
+	h      sha3.ShakeHash
+	domain []byte
+}
+
+func newXOFStream(newHash func() sha3.ShakeHash, k int, domain []byte) *xofStream {
+	h := newHash()
+	if len(domain) > 255 {
+		// DST = H("H2C-OVERSIZE-DST-" || a_very_long_DST, ceil(2 * k / 8))
+		_, _ = h.Write([]byte("H2C-OVERSIZE-DST-"))
+		_, _ = h.Write(domain)
+		domain = make([]byte, (2*k+7)/8)
+		_, _ = h.Read(domain)
+		h.Reset()
+	}
+	return &xofStream{h, domain}
+}
+
+func (s *xofStream) reset() {
+	s.h.Reset()
+}
+
+func (s *xofStream) Write(msg []byte) (int, error) {
+	return s.h.Write(msg)
+}
+
+func (s *xofStream) expand(outLen int) ([]byte, error) {
+	if outLen <= 0 || outLen > 65535 {
+		return nil, fmt.Errorf("invalid output length")
+	}
+	h, domain := s.h, s.domain
+	// msg_prime = msg || I2OSP(len_in_bytes, 2) || DST || I2OSP(len(DST), 1)
+	_, _ = h.Write([]byte{uint8(outLen >> 8), uint8(outLen)})
+	_, _ = h.Write(domain)
+	_, _ = h.Write([]byte{uint8(len(domain))})
+	out := make([]byte, outLen)
+	_, _ = h.Read(out)
+	return out, nil
+}
@@ -1,11 +1,30 @@
 package bls12381
 
-// isogenyMapG1 applies 11-isogeny map for BLS12-381 G1 defined at draft-irtf-cfrg-hash-to-curve-06.
+import (
+	"fmt"
+	"math/big"
+)
+
+// isogenyMapG1 applies 11-isogeny map for BLS12-381 G1 defined at RFC 9380.
 func isogenyMapG1(x, y *fe) {
-	// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06#appendix-C.2
+	xNum, xDen, yNum, yDen := new(fe), new(fe), new(fe), new(fe)
+	isogenyMapG1Fraction(xNum, xDen, yNum, yDen, x)
+	inverse(xDen, xDen)
+	inverse(yDen, yDen)
+	mul(xNum, xNum, xDen)
+	mul(yNum, yNum, yDen)
+	mul(yNum, yNum, y)
+	x.set(xNum)
+	y.set(yNum)
+}
+
+// isogenyMapG1Fraction evaluates numerator and denominator polynomials of 11-isogeny map at x
+// so that the caller can choose how to invert denominators.
+// Mapped point is (xNum / xDen, y * yNum / yDen).
+func isogenyMapG1Fraction(xNum, xDen, yNum, yDen, x *fe) {
+	// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-E.2
 	params := isogenyConstansG1
 	degree := 15
-	xNum, xDen, yNum, yDen := new(fe), new(fe), new(fe), new(fe)
 	xNum.set(params[0][degree])
 	xDen.set(params[1][degree])
 	yNum.set(params[2][degree])
@@ -20,24 +39,188 @@ func isogenyMapG1(x, y *fe) {
 		add(yNum, yNum, params[2][i])
 		add(yDen, yDen, params[3][i])
 	}
-	inverse(xDen, xDen)
-	inverse(yDen, yDen)
-	mul(xNum, xNum, xDen)
-	mul(yNum, yNum, yDen)
-	mul(yNum, yNum, y)
-	x.set(xNum)
-	y.set(yNum)
 }
 
-// isogenyMapG2 applies 11-isogeny map for BLS12-381 G1 defined at draft-irtf-cfrg-hash-to-curve-06.
+// isogenyMapG1Projective applies 11-isogeny map without inversion and sets p to the mapped point
+// in homogeneous projective coordinates (xNum * yDen : y * yNum * xDen : xDen * yDen).
+// Points in the kernel are mapped to (0 : 1 : 0). It does not branch on the input.
+func isogenyMapG1Projective(p *PointG1, x, y *fe) {
+	xNum, xDen, yNum, yDen := new(fe), new(fe), new(fe), new(fe)
+	isogenyMapG1Fraction(xNum, xDen, yNum, yDen, x)
+	mul(&p[0], xNum, yDen)
+	mul(&p[1], yNum, xDen)
+	mul(&p[1], &p[1], y)
+	mul(&p[2], xDen, yDen)
+	isInfinity := isZeroCT(&p[2])
+	cmov(&p[0], &p[0], zero(), isInfinity)
+	cmov(&p[1], &p[1], one(), isInfinity)
+}
+
+// isogenyMapG1Inverse returns a point on the isogenous curve that 11-isogeny map sends to (x, y).
+// Kernel of the map is rational and 11 divides the cofactor, so only points in a subgroup of index 11
+// such as G1 points have preimages and each has 11 of them. Their x coordinates together with
+// those of the preimages of (x, -y) are the roots of xNum(X) - x * xDen(X), one of them is returned.
+// It is not constant time.
+func isogenyMapG1Inverse(x, y *fe) (*fe, *fe, error) {
+	params := isogenyConstansG1
+	f := make([]fe, 16)
+	t := new(fe)
+	for i := range f {
+		mul(t, x, params[1][i])
+		sub(&f[i], params[0][i], t)
+	}
+	x0, err := polyRoot(polyMonic(polyTrim(f)))
+	if err != nil {
+		return nil, nil, err
+	}
+	// y = y0 * yNum(x0) / yDen(x0)
+	xNum, xDen, yNum, yDen := new(fe), new(fe), new(fe), new(fe)
+	isogenyMapG1Fraction(xNum, xDen, yNum, yDen, x0)
+	if yNum.isZero() {
+		return nil, nil, fmt.Errorf("point has no preimage")
+	}
+	y0 := new(fe)
+	inverse(yNum, yNum)
+	mul(y0, y, yDen)
+	mul(y0, y0, yNum)
+	return x0, y0, nil
+}
+
+// Polynomials over base field are coefficients in increasing degree.
+
+func polyTrim(a []fe) []fe {
+	for len(a) > 0 && a[len(a)-1].isZero() {
+		a = a[:len(a)-1]
+	}
+	return a
+}
+
+func polyMonic(a []fe) []fe {
+	if len(a) == 0 {
+		return a
+	}
+	r, c := make([]fe, len(a)), new(fe)
+	inverse(c, &a[len(a)-1])
+	for i := range a {
+		mul(&r[i], &a[i], c)
+	}
+	return r
+}
+
+// polyMod returns a mod m where m is monic.
+func polyMod(a, m []fe) []fe {
+	r, t := make([]fe, len(a)), new(fe)
+	copy(r, a)
+	r = polyTrim(r)
+	for len(r) >= len(m) {
+		c, k := r[len(r)-1], len(r)-len(m)
+		for i := range m {
+			mul(t, &c, &m[i])
+			sub(&r[k+i], &r[k+i], t)
+		}
+		r = polyTrim(r)
+	}
+	return r
+}
+
+func polyMulMod(a, b, m []fe) []fe {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	r, t := make([]fe, len(a)+len(b)-1), new(fe)
+	for i := range a {
+		for j := range b {
+			mul(t, &a[i], &b[j])
+			add(&r[i+j], &r[i+j], t)
+		}
+	}
+	return polyMod(r, m)
+}
+
+func polyExpMod(a []fe, e *big.Int, m []fe) []fe {
+	r := []fe{*one()}
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		r = polyMulMod(r, r, m)
+		if e.Bit(i) == 1 {
+			r = polyMulMod(r, a, m)
+		}
+	}
+	return r
+}
+
+// polyGCD returns monic greatest common divisor of a and b.
+func polyGCD(a, b []fe) []fe {
+	a, b = polyTrim(a), polyTrim(b)
+	for len(b) > 0 {
+		b = polyMonic(b)
+		a, b = b, polyMod(a, b)
+	}
+	return polyMonic(a)
+}
+
+// polyRoot returns a root of monic f which is expected to be a product of distinct linear factors.
+// Roots are split with Cantor-Zassenhaus method where gcd(f, (X + a)^((p - 1) / 2) - 1)
+// collects roots r such that r + a is a non zero square.
+func polyRoot(f []fe) (*fe, error) {
+	for a := uint64(0); len(f) > 2; a++ {
+		if a == 256 {
+			return nil, fmt.Errorf("polynomial doesn't split")
+		}
+		s := polyExpMod([]fe{*new(fe).setUint(a), *one()}, pMinus1Over2, f)
+		if len(s) == 0 {
+			s = make([]fe, 1)
+		}
+		sub(&s[0], &s[0], one())
+		if h := polyGCD(f, s); len(h) > 1 && len(h) < len(f) {
+			f = h
+		}
+	}
+	if len(f) != 2 {
+		return nil, fmt.Errorf("polynomial has no root")
+	}
+	r := new(fe)
+	neg(r, &f[0])
+	return r, nil
+}
+
+// isogenyMapG2 applies 3-isogeny map for BLS12-381 G2 defined at RFC 9380.
 func isogenyMapG2(e *fp2, x, y *fe2) {
 	if e == nil {
 		e = newFp2()
 	}
-	// https://tools.ietf.org/html/draft-irtf-cfrg-hash-to-curve-06#appendix-C.2
+	xNum, xDen, yNum, yDen := new(fe2), new(fe2), new(fe2), new(fe2)
+	isogenyMapG2Fraction(e, xNum, xDen, yNum, yDen, x)
+	e.inverse(xDen, xDen)
+	e.inverse(yDen, yDen)
+	e.mul(xNum, xNum, xDen)
+	e.mul(yNum, yNum, yDen)
+	e.mul(yNum, yNum, y)
+	e.copy(x, xNum)
+	e.copy(y, yNum)
+}
+
+// isogenyMapG2Projective applies 3-isogeny map without inversion and sets p to the mapped point
+// in homogeneous projective coordinates (xNum * yDen : y * yNum * xDen : xDen * yDen).
+// Points in the kernel are mapped to (0 : 1 : 0). It does not branch on the input.
+func isogenyMapG2Projective(e *fp2, p *PointG2, x, y *fe2) {
+	xNum, xDen, yNum, yDen := new(fe2), new(fe2), new(fe2), new(fe2)
+	isogenyMapG2Fraction(e, xNum, xDen, yNum, yDen, x)
+	e.mul(&p[0], xNum, yDen)
+	e.mul(&p[1], yNum, xDen)
+	e.mul(&p[1], &p[1], y)
+	e.mul(&p[2], xDen, yDen)
+	isInfinity := e.isZeroCT(&p[2])
+	e.cmov(&p[0], &p[0], e.zero(), isInfinity)
+	e.cmov(&p[1], &p[1], e.one(), isInfinity)
+}
+
+// isogenyMapG2Fraction evaluates numerator and denominator polynomials of 3-isogeny map at x
+// so that the caller can choose how to invert denominators.
+// Mapped point is (xNum / xDen, y * yNum / yDen).
+func isogenyMapG2Fraction(e *fp2, xNum, xDen, yNum, yDen, x *fe2) {
+	// https://www.rfc-editor.org/rfc/rfc9380.html#appendix-E.3
 	params := isogenyConstantsG2
 	degree := 3
-	xNum, xDen, yNum, yDen := new(fe2), new(fe2), new(fe2), new(fe2)
 	e.copy(xNum, params[0][degree])
 	e.copy(xDen, params[1][degree])
 	e.copy(yNum, params[2][degree])
@@ -52,13 +235,6 @@ func isogenyMapG2(e *fp2, x, y *fe2) {
 		e.add(yNum, yNum, params[2][i])
 		e.add(yDen, yDen, params[3][i])
 	}
-	e.inverse(xDen, xDen)
-	e.inverse(yDen, yDen)
-	e.mul(xNum, xNum, xDen)
-	e.mul(yNum, yNum, yDen)
-	e.mul(yNum, yNum, y)
-	e.copy(x, xNum)
-	e.copy(y, yNum)
 }
 
 var isogenyConstansG1 = [4][16]*fe{
@@ -1,14 +1,36 @@
 package bls12381
 
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
 type pair struct {
-	g1 *PointG1
-	g2 *PointG2
+	g1       *PointG1
+	g2       *PointG2
+	prepared *PreparedG2
 }
 
 func newPair(g1 *PointG1, g2 *PointG2) pair {
-	return pair{g1, g2}
+	return pair{g1: g1, g2: g2}
+}
+
+// PreparedG2 holds Miller loop line coefficients of a G2 point,
+// so that pairings against the same point do not recompute them.
+type PreparedG2 struct {
+	coeffs   [68][3]fe2
+	infinity bool
 }
 
+// affineMillerLoopThreshold is the number of pairs from which Miller loop runs in affine coordinates.
+// Affine Miller loop output differs from the projective one by factors that final exponentiation eliminates,
+// so it is used only when Miller loop output is not exposed.
+const affineMillerLoopThreshold = 24
+
+// preparedG2Size is the size of serialized prepared G2 point.
+const preparedG2Size = 68 * 3 * 96
+
 // Engine is BLS12-381 elliptic curve pairing engine
 type Engine struct {
 	G1   *G1
@@ -16,7 +38,8 @@ type Engine struct {
 	fp12 *fp12
 	fp2  *fp2
 	pairingEngineTemp
-	pairs []pair
+	pairs   []pair
+	workers []*Engine
 }
 
 // NewEngine creates new pairing engine insteace.
@@ -60,6 +83,80 @@ func (e *Engine) AddPair(g1 *PointG1, g2 *PointG2) *Engine {
 	return e
 }
 
+// AddPairPrepared adds a g1 point and a prepared g2 point pair to pairing engine.
+// Prepared and unprepared pairs can be added to the same engine.
+func (e *Engine) AddPairPrepared(g1 *PointG1, g2 *PreparedG2) *Engine {
+	p := pair{g1: g1, prepared: g2}
+	if !e.isZero(p) {
+		e.affine(p)
+		e.pairs = append(e.pairs, p)
+	}
+	return e
+}
+
+// PrepareG2 computes Miller loop line coefficients of given G2 point.
+// Input point is not modified.
+func (e *Engine) PrepareG2(g2 *PointG2) *PreparedG2 {
+	prepared := &PreparedG2{}
+	if e.G2.IsZero(g2) {
+		prepared.infinity = true
+		return prepared
+	}
+	r := e.G2.New().Set(g2)
+	e.G2.Affine(r)
+	e.preCompute(&prepared.coeffs, r)
+	return prepared
+}
+
+// ToBytes serializes prepared G2 point into bytes.
+// Line coefficients are written in order, each as a 96 bytes Fp2 element.
+// All zero output stands for point at infinity.
+func (p *PreparedG2) ToBytes() []byte {
+	out := make([]byte, preparedG2Size)
+	if p.infinity {
+		return out
+	}
+	fp2 := newFp2()
+	for i := 0; i < 68; i++ {
+		for j := 0; j < 3; j++ {
+			k := (i*3 + j) * 96
+			copy(out[k:k+96], fp2.toBytes(&p.coeffs[i][j]))
+		}
+	}
+	return out
+}
+
+// NewPreparedG2FromBytes deserializes prepared G2 point.
+// Coefficients are only checked to be valid field elements,
+// input is expected to be produced by PreparedG2.ToBytes of a trusted point.
+func NewPreparedG2FromBytes(in []byte) (*PreparedG2, error) {
+	if len(in) != preparedG2Size {
+		return nil, fmt.Errorf("input string should be %d bytes", preparedG2Size)
+	}
+	p := &PreparedG2{infinity: true}
+	for _, b := range in {
+		if b != 0 {
+			p.infinity = false
+			break
+		}
+	}
+	if p.infinity {
+		return p, nil
+	}
+	fp2 := newFp2()
+	for i := 0; i < 68; i++ {
+		for j := 0; j < 3; j++ {
+			k := (i*3 + j) * 96
+			c, err := fp2.fromBytes(in[k : k+96])
+			if err != nil {
+				return nil, err
+			}
+			p.coeffs[i][j] = *c
+		}
+	}
+	return p, nil
+}
+
 // AddPairInv adds a G1, G2 point pair to pairing engine. G1 point is negated.
 func (e *Engine) AddPairInv(g1 *PointG1, g2 *PointG2) *Engine {
 	e.G1.Neg(g1, g1)
@@ -67,6 +164,20 @@ func (e *Engine) AddPairInv(g1 *PointG1, g2 *PointG2) *Engine {
 	return e
 }
 
+// SetWorkers sets the number of goroutines that Miller loop of added pairs is split into.
+// Non positive value sets it to GOMAXPROCS. Results are identical to the serial computation.
+// Default is a single worker.
+func (e *Engine) SetWorkers(n int) *Engine {
+	if n < 1 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	e.workers = make([]*Engine, n-1)
+	for i := range e.workers {
+		e.workers[i] = NewEngine()
+	}
+	return e
+}
+
 // Reset deletes added pairs.
 func (e *Engine) Reset() *Engine {
 	e.pairs = []pair{}
@@ -74,12 +185,17 @@ func (e *Engine) Reset() *Engine {
 }
 
 func (e *Engine) isZero(p pair) bool {
+	if p.prepared != nil {
+		return e.G1.IsZero(p.g1) || p.prepared.infinity
+	}
 	return e.G1.IsZero(p.g1) || e.G2.IsZero(p.g2)
 }
 
 func (e *Engine) affine(p pair) {
 	e.G1.Affine(p.g1)
-	e.G2.Affine(p.g2)
+	if p.prepared == nil {
+		e.G2.Affine(p.g2)
+	}
 }
 
 func (e *Engine) doublingStep(coeff *[3]fe2, r *PointG2) {
@@ -166,11 +282,59 @@ func (e *Engine) preCompute(ellCoeffs *[68][3]fe2, twistPoint *PointG2) {
 	}
 }
 
-func (e *Engine) millerLoop(f *fe12) {
+func (e *Engine) millerLoop(f *fe12, affine bool) {
+	workers := len(e.workers) + 1
+	if workers > len(e.pairs) {
+		workers = len(e.pairs)
+	}
+	if workers < 2 {
+		e.millerLoopSerial(f, affine)
+		return
+	}
+	// Miller loop of a pair set is the product of Miller loops of its parts.
+	// Each part runs on a worker engine with its own temporaries and
+	// the caller engine itself takes the first one.
+	pairs := e.pairs
+	results := make([]fe12, workers)
+	var wg sync.WaitGroup
+	for i := 1; i < workers; i++ {
+		w := e.workers[i-1]
+		w.pairs = pairs[i*len(pairs)/workers : (i+1)*len(pairs)/workers]
+		wg.Add(1)
+		go func(w *Engine, f *fe12) {
+			defer wg.Done()
+			w.millerLoopSerial(f, affine)
+			w.pairs = nil
+		}(w, &results[i])
+	}
+	e.pairs = pairs[:len(pairs)/workers]
+	e.millerLoopSerial(&results[0], affine)
+	e.pairs = pairs
+	wg.Wait()
+	e.fp12.copy(f, &results[0])
+	for i := 1; i < workers; i++ {
+		e.fp12.mulAssign(f, &results[i])
+	}
+}
+
+func (e *Engine) millerLoopSerial(f *fe12, affine bool) {
+	if affine && len(e.pairs) >= affineMillerLoopThreshold {
+		e.millerLoopAffine(f)
+		return
+	}
+	e.millerLoopProjective(f)
+}
+
+func (e *Engine) millerLoopProjective(f *fe12) {
 	pairs := e.pairs
-	ellCoeffs := make([][68][3]fe2, len(pairs))
+	ellCoeffs := make([]*[68][3]fe2, len(pairs))
 	for i := 0; i < len(pairs); i++ {
-		e.preCompute(&ellCoeffs[i], pairs[i].g2)
+		if pairs[i].prepared != nil {
+			ellCoeffs[i] = &pairs[i].prepared.coeffs
+			continue
+		}
+		ellCoeffs[i] = new([68][3]fe2)
+		e.preCompute(ellCoeffs[i], pairs[i].g2)
 	}
 	fp12, fp2 := e.fp12, e.fp2
 	t := e.t2
@@ -198,6 +362,130 @@ func (e *Engine) millerLoop(f *fe12) {
 	fp12.conjugate(f, f)
 }
 
+// affineDoublingStep doubles affine accumulators and sets slopes of tangent lines at them.
+// Tangent lines are scaled to (lambda * x - y, -lambda, 1).
+func (e *Engine) affineDoublingStep(c0, lambda, rx, ry []fe2) {
+	fp2, t := e.fp2, e.t2
+	for k := range rx {
+		fp2.double(&lambda[k], &ry[k])
+	}
+	fp2.inverseBatch(lambda)
+	for k := range rx {
+		// lambda = 3 * x^2 / 2 * y
+		fp2.square(t[0], &rx[k])
+		fp2.double(t[1], t[0])
+		fp2.addAssign(t[1], t[0])
+		fp2.mulAssign(&lambda[k], t[1])
+		// c0 = lambda * x - y
+		fp2.mul(&c0[k], &lambda[k], &rx[k])
+		fp2.subAssign(&c0[k], &ry[k])
+		// x' = lambda^2 - 2 * x
+		// y' = lambda * (x - x') - y
+		fp2.square(t[0], &lambda[k])
+		fp2.double(t[1], &rx[k])
+		fp2.subAssign(t[0], t[1])
+		fp2.sub(t[1], &rx[k], t[0])
+		fp2.mulAssign(t[1], &lambda[k])
+		fp2.sub(&ry[k], t[1], &ry[k])
+		fp2.copy(&rx[k], t[0])
+	}
+}
+
+// affineAdditionStep adds q to affine accumulators and sets slopes of lines through them.
+// Lines are scaled to (lambda * x - y, -lambda, 1).
+func (e *Engine) affineAdditionStep(c0, lambda, rx, ry []fe2, q []*PointG2) {
+	fp2, t := e.fp2, e.t2
+	for k := range rx {
+		fp2.sub(&lambda[k], &rx[k], &q[k][0])
+	}
+	fp2.inverseBatch(lambda)
+	for k := range rx {
+		// lambda = (y - qy) / (x - qx)
+		fp2.sub(t[0], &ry[k], &q[k][1])
+		fp2.mulAssign(&lambda[k], t[0])
+		// c0 = lambda * x - y
+		fp2.mul(&c0[k], &lambda[k], &rx[k])
+		fp2.subAssign(&c0[k], &ry[k])
+		// x' = lambda^2 - x - qx
+		// y' = lambda * (x - x') - y
+		fp2.square(t[0], &lambda[k])
+		fp2.subAssign(t[0], &rx[k])
+		fp2.subAssign(t[0], &q[k][0])
+		fp2.sub(t[1], &rx[k], t[0])
+		fp2.mulAssign(t[1], &lambda[k])
+		fp2.sub(&ry[k], t[1], &ry[k])
+		fp2.copy(&rx[k], t[0])
+	}
+}
+
+// millerLoopAffine keeps G2 accumulators in affine coordinates and inverts
+// denominators of all pairs together at each step, which pays off for many pairs.
+// Lines differ from the projective ones by Fp2 factors that are eliminated by final exponentiation.
+func (e *Engine) millerLoopAffine(f *fe12) {
+	pairs := e.pairs
+	fp12, fp2 := e.fp12, e.fp2
+	t := e.t2
+	// accumulators of unprepared pairs
+	idx := make([]int, 0, len(pairs))
+	q := make([]*PointG2, 0, len(pairs))
+	for i := range pairs {
+		if pairs[i].prepared == nil {
+			idx = append(idx, i)
+			q = append(q, pairs[i].g2)
+		}
+	}
+	n := len(idx)
+	buf := make([]fe2, 4*n)
+	rx, ry, c0, lambda := buf[:n], buf[n:2*n], buf[2*n:3*n], buf[3*n:]
+	for k := range q {
+		fp2.copy(&rx[k], &q[k][0])
+		fp2.copy(&ry[k], &q[k][1])
+	}
+	// lines are divided by y of G1 points so that the last coefficient stays one
+	// px = -x / y, py = 1 / y
+	px, py := make([]fe, n), make([]fe, n)
+	for k, i := range idx {
+		py[k].set(&pairs[i].g1[1])
+	}
+	inverseBatch(py)
+	for k, i := range idx {
+		mul(&px[k], &pairs[i].g1[0], &py[k])
+		neg(&px[k], &px[k])
+	}
+	lines := func(j int) {
+		for i := range pairs {
+			if pairs[i].prepared == nil {
+				continue
+			}
+			coeffs := &pairs[i].prepared.coeffs[j]
+			fp2.mulByFq(t[0], &coeffs[2], &pairs[i].g1[1])
+			fp2.mulByFq(t[1], &coeffs[1], &pairs[i].g1[0])
+			fp12.mulBy014Assign(f, &coeffs[0], t[1], t[0])
+		}
+		for k := range idx {
+			fp2.mulByFq(t[0], &c0[k], &py[k])
+			fp2.mulByFq(t[1], &lambda[k], &px[k])
+			fp12.mulBy014OneAssign(f, t[0], t[1])
+		}
+	}
+	fp12.copy(f, fp12.one())
+	j := 0
+	for i := 62; /* x.BitLen() - 2 */ i >= 0; i-- {
+		if i != 62 {
+			fp12.square(f, f)
+		}
+		e.affineDoublingStep(c0, lambda, rx, ry)
+		lines(j)
+		if x.Bit(i) != 0 {
+			j++
+			e.affineAdditionStep(c0, lambda, rx, ry, q)
+			lines(j)
+		}
+		j++
+	}
+	fp12.conjugate(f, f)
+}
+
 func (e *Engine) exp(c, a *fe12) {
 	fp12 := e.fp12
 	fp12.cyclotomicExp(c, a, x)
@@ -214,30 +502,34 @@ func (e *Engine) finalExp(f *fe12) {
 	fp12.copy(&t[1], &t[2])
 	fp12.frobeniusMapAssign(&t[2], 2)
 	fp12.mulAssign(&t[2], &t[1])
-	fp12.cyclotomicSquare(&t[1], &t[2])
-	fp12.conjugate(&t[1], &t[1])
 	// hard part
-	e.exp(&t[3], &t[2])
-	fp12.cyclotomicSquare(&t[4], &t[3])
-	fp12.mul(&t[5], &t[1], &t[3])
-	e.exp(&t[1], &t[5])
-	e.exp(&t[0], &t[1])
-	e.exp(&t[6], &t[0])
-	fp12.mulAssign(&t[6], &t[4])
-	e.exp(&t[4], &t[6])
-	fp12.conjugate(&t[5], &t[5])
-	fp12.mulAssign(&t[4], &t[5])
-	fp12.mulAssign(&t[4], &t[2])
-	fp12.conjugate(&t[5], &t[2])
-	fp12.mulAssign(&t[1], &t[2])
-	fp12.frobeniusMapAssign(&t[1], 3)
-	fp12.mulAssign(&t[6], &t[5])
-	fp12.frobeniusMapAssign(&t[6], 1)
-	fp12.mulAssign(&t[3], &t[0])
-	fp12.frobeniusMapAssign(&t[3], 2)
-	fp12.mulAssign(&t[3], &t[1])
-	fp12.mulAssign(&t[3], &t[6])
-	fp12.mul(f, &t[3], &t[4])
+	// https://eprint.iacr.org/2020/875
+	// 3 * (p^4 - p^2 + 1) / r = (x - 1)^2 * (x + p) * (x^2 + p^2 - 1) + 3
+	// Chain takes five exponentiations by x, two Frobenius maps and a few multiplications,
+	// see cost of BLS12 curves at https://eprint.iacr.org/2020/875
+	// inverses are conjugates in the cyclotomic subgroup
+	fp12.cyclotomicSquare(&t[0], &t[2])
+	fp12.mulAssign(&t[0], &t[2])
+	// t1 = f^((x - 1)^2)
+	e.exp(&t[1], &t[2])
+	fp12.conjugate(&t[3], &t[2])
+	fp12.mulAssign(&t[1], &t[3])
+	e.exp(&t[3], &t[1])
+	fp12.conjugate(&t[1], &t[1])
+	fp12.mulAssign(&t[1], &t[3])
+	// t1 = t1^(x + p)
+	e.exp(&t[3], &t[1])
+	fp12.frobeniusMapAssign(&t[1], 1)
+	fp12.mulAssign(&t[1], &t[3])
+	// t1 = t1^(x^2 + p^2 - 1)
+	e.exp(&t[3], &t[1])
+	e.exp(&t[4], &t[3])
+	fp12.frobeniusMap(&t[3], &t[1], 2)
+	fp12.conjugate(&t[1], &t[1])
+	fp12.mulAssign(&t[1], &t[4])
+	fp12.mulAssign(&t[1], &t[3])
+	// f = t1 * f^3
+	fp12.mul(f, &t[1], &t[0])
 }
 
 func (e *Engine) calculate() *fe12 {
@@ -245,7 +537,7 @@ func (e *Engine) calculate() *fe12 {
 	if len(e.pairs) == 0 {
 		return f
 	}
-	e.millerLoop(f)
+	e.millerLoop(f, true)
 	e.finalExp(f)
 	return f
 }
@@ -262,6 +554,138 @@ func (e *Engine) Result() *E {
 	return r
 }
 
+// MillerLoop computes Miller loop of added pairs and returns its product without final exponentiation.
+// Output is not a target group element until it is passed to FinalExp,
+// Miller loop outputs of different pair sets can be multiplied before that.
+// Output is the same for any number of workers.
+// Like Result, it resets added pairs.
+func (e *Engine) MillerLoop() *E {
+	f := e.fp12.one()
+	if len(e.pairs) != 0 {
+		e.millerLoop(f, false)
+	}
+	e.Reset()
+	return f
+}
+
+// FinalExp applies final exponentiation to a Miller loop output and returns target group element.
+// Input is not modified.
+func (e *Engine) FinalExp(f *E) *E {
+	r := e.fp12.new()
+	e.fp12.copy(r, f)
+	e.finalExp(r)
+	return r
+}
+
+// validationCacheSize is the number of points per group a validating engine remembers.
+const validationCacheSize = 1024
+
+// ValidatingEngine is a pairing engine that checks points before adding them.
+// Points that are not on curve or not in correct subgroup are rejected with an error.
+// Points that passed the checks are remembered so that they are not checked again.
+type ValidatingEngine struct {
+	*Engine
+	g1Cache map[[2]fe]struct{}
+	g2Cache map[[2]fe2]struct{}
+}
+
+// NewValidatingEngine creates new pairing engine that validates added points.
+func NewValidatingEngine() *ValidatingEngine {
+	return &ValidatingEngine{
+		Engine:  NewEngine(),
+		g1Cache: make(map[[2]fe]struct{}),
+		g2Cache: make(map[[2]fe2]struct{}),
+	}
+}
+
+// AddPair validates points and adds the pair to pairing engine.
+func (e *ValidatingEngine) AddPair(g1 *PointG1, g2 *PointG2) error {
+	if err := e.validateG1(g1); err != nil {
+		return err
+	}
+	if err := e.validateG2(g2); err != nil {
+		return err
+	}
+	e.Engine.AddPair(g1, g2)
+	return nil
+}
+
+// AddPairInv validates points and adds the pair to pairing engine. G1 point is negated.
+func (e *ValidatingEngine) AddPairInv(g1 *PointG1, g2 *PointG2) error {
+	if err := e.validateG1(g1); err != nil {
+		return err
+	}
+	if err := e.validateG2(g2); err != nil {
+		return err
+	}
+	e.Engine.AddPairInv(g1, g2)
+	return nil
+}
+
+// AddPairPrepared validates g1 point and adds the pair to pairing engine.
+// Prepared points are expected to be created with PrepareG2 of a validating engine.
+func (e *ValidatingEngine) AddPairPrepared(g1 *PointG1, g2 *PreparedG2) error {
+	if err := e.validateG1(g1); err != nil {
+		return err
+	}
+	e.Engine.AddPairPrepared(g1, g2)
+	return nil
+}
+
+// PrepareG2 validates given G2 point and computes its Miller loop line coefficients.
+func (e *ValidatingEngine) PrepareG2(g2 *PointG2) (*PreparedG2, error) {
+	if err := e.validateG2(g2); err != nil {
+		return nil, err
+	}
+	return e.Engine.PrepareG2(g2), nil
+}
+
+func (e *ValidatingEngine) validateG1(p *PointG1) error {
+	g := e.G1
+	if g.IsZero(p) {
+		return nil
+	}
+	g.Affine(p)
+	key := [2]fe{p[0], p[1]}
+	if _, ok := e.g1Cache[key]; ok {
+		return nil
+	}
+	if !g.IsOnCurve(p) {
+		return fmt.Errorf("g1 point is not on curve")
+	}
+	if !g.InCorrectSubgroup(p) {
+		return fmt.Errorf("g1 point is not on correct subgroup")
+	}
+	if len(e.g1Cache) >= validationCacheSize {
+		e.g1Cache = make(map[[2]fe]struct{})
+	}
+	e.g1Cache[key] = struct{}{}
+	return nil
+}
+
+func (e *ValidatingEngine) validateG2(p *PointG2) error {
+	g := e.G2
+	if g.IsZero(p) {
+		return nil
+	}
+	g.Affine(p)
+	key := [2]fe2{p[0], p[1]}
+	if _, ok := e.g2Cache[key]; ok {
+		return nil
+	}
+	if !g.IsOnCurve(p) {
+		return fmt.Errorf("g2 point is not on curve")
+	}
+	if !g.InCorrectSubgroup(p) {
+		return fmt.Errorf("g2 point is not on correct subgroup")
+	}
+	if len(e.g2Cache) >= validationCacheSize {
+		e.g2Cache = make(map[[2]fe2]struct{})
+	}
+	e.g2Cache[key] = struct{}{}
+	return nil
+}
+
 // GT returns target group instance.
 func (e *Engine) GT() *GT {
 	return NewGT()
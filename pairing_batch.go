@@ -0,0 +1,143 @@
+package bls12381
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// PairingBatchVerifier checks many pairing product equations of the form
+// e(P_0, Q_0) * e(P_1, Q_1) * ... * e(P_n, Q_n) == 1 with a single multi pairing.
+// Each equation is raised to a random 128 bit scalar before they are multiplied together,
+// so that a set with a false equation passes with probability about 2^-128.
+// The bound holds for points in prime order subgroups, so points are checked to be on curve
+// and in correct subgroup when they are added.
+// G1 points of all equations that are paired with the same G2 point are merged with multi exponentiation.
+type PairingBatchVerifier struct {
+	engine    *ValidatingEngine
+	rand      io.Reader
+	equations []pairingEquation
+}
+
+type pairingEquation struct {
+	g1 []*PointG1
+	g2 []*PointG2
+}
+
+// NewPairingBatchVerifier creates a new batch verifier that draws scalars from given reader.
+// If reader is nil crypto/rand is used.
+func NewPairingBatchVerifier(r io.Reader) *PairingBatchVerifier {
+	if r == nil {
+		r = rand.Reader
+	}
+	return &PairingBatchVerifier{engine: NewValidatingEngine(), rand: r}
+}
+
+// Add adds an equation e(g1[0], g2[0]) * e(g1[1], g2[1]) * ... == 1 to the batch.
+// Points are not copied and they are converted to affine form.
+// Equation is rejected if any of its points is not on curve or not in correct subgroup.
+func (v *PairingBatchVerifier) Add(g1 []*PointG1, g2 []*PointG2) error {
+	if len(g1) != len(g2) {
+		return fmt.Errorf("g1 and g2 point vectors should be in same length")
+	}
+	for i := range g1 {
+		if err := v.engine.validateG1(g1[i]); err != nil {
+			return err
+		}
+		if err := v.engine.validateG2(g2[i]); err != nil {
+			return err
+		}
+	}
+	v.equations = append(v.equations, pairingEquation{g1, g2})
+	return nil
+}
+
+// Reset deletes added equations.
+func (v *PairingBatchVerifier) Reset() {
+	v.equations = nil
+}
+
+// Verify checks added equations and returns indexes of the false ones in the order they were added.
+// If combined check fails, halves of the batch are checked recursively to find false equations.
+// Returned slice is empty if all equations hold.
+func (v *PairingBatchVerifier) Verify() ([]int, error) {
+	n := len(v.equations)
+	scalars := make([]*big.Int, n)
+	buf := make([]byte, 16)
+	for i := 0; i < n; i++ {
+		if _, err := io.ReadFull(v.rand, buf); err != nil {
+			return nil, err
+		}
+		scalars[i] = new(big.Int).SetBytes(buf)
+		if scalars[i].Sign() == 0 {
+			scalars[i].SetUint64(1)
+		}
+	}
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	return v.search(idx, scalars, false), nil
+}
+
+// search returns false equations among given ones.
+// If the caller already knows that product of equations is not one, check is skipped.
+func (v *PairingBatchVerifier) search(idx []int, scalars []*big.Int, failing bool) []int {
+	if len(idx) == 0 {
+		return nil
+	}
+	if !failing && v.check(idx, scalars) {
+		return nil
+	}
+	if len(idx) == 1 {
+		return []int{idx[0]}
+	}
+	h := len(idx) / 2
+	left := v.search(idx[:h], scalars, false)
+	// if left half holds, the right half must contain a false equation
+	right := v.search(idx[h:], scalars, len(left) == 0)
+	return append(left, right...)
+}
+
+// check combines given equations with their scalars and runs a single multi pairing.
+func (v *PairingBatchVerifier) check(idx []int, scalars []*big.Int) bool {
+	e := v.engine.Engine
+	G1, G2 := e.G1, e.G2
+	groups := make(map[[2]fe2]int)
+	var q []*PointG2
+	var points [][]*PointG1
+	var powers [][]*big.Int
+	for _, i := range idx {
+		eq := v.equations[i]
+		for j := range eq.g1 {
+			if G1.IsZero(eq.g1[j]) || G2.IsZero(eq.g2[j]) {
+				continue
+			}
+			G2.Affine(eq.g2[j])
+			key := [2]fe2{eq.g2[j][0], eq.g2[j][1]}
+			k, ok := groups[key]
+			if !ok {
+				k = len(q)
+				groups[key] = k
+				q = append(q, eq.g2[j])
+				points = append(points, nil)
+				powers = append(powers, nil)
+			}
+			points[k] = append(points[k], eq.g1[j])
+			powers[k] = append(powers[k], scalars[i])
+		}
+	}
+	for k := range q {
+		r := G1.New()
+		if len(points[k]) == 1 {
+			G1.MulScalar(r, points[k][0], powers[k][0])
+		} else {
+			G1.MultiExp(r, points[k], powers[k])
+		}
+		e.AddPair(r, q[k])
+	}
+	ok := e.Check()
+	e.Reset()
+	return ok
+}
@@ -1,7 +1,11 @@
 package bls12381
 
 import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
 	"math/big"
+	mrand "math/rand"
 	"testing"
 )
 
@@ -207,6 +211,544 @@ func TestPairingMulti(t *testing.T) {
 	}
 }
 
+func TestPairingFinalExp(t *testing.T) {
+	// f^((p^12 - 1) / r * 3)
+	bls := NewEngine()
+	fp12 := bls.fp12
+	p := modulus.big()
+	p2 := new(big.Int).Mul(p, p)
+	e := new(big.Int).Mul(p2, p2)
+	e.Sub(e, p2).Add(e, big.NewInt(1)).Div(e, q)
+	e.Mul(e, big.NewInt(3))
+	for i := 0; i < fuz; i++ {
+		f, _ := fp12.rand(rand.Reader)
+		expected, t0 := fp12.new(), fp12.new()
+		// f^((p^6 - 1) * (p^2 + 1))
+		fp12.conjugate(expected, f)
+		fp12.inverse(t0, f)
+		fp12.mulAssign(expected, t0)
+		fp12.frobeniusMap(t0, expected, 2)
+		fp12.mulAssign(expected, t0)
+		fp12.exp(expected, expected, e)
+		bls.finalExp(f)
+		if !fp12.equal(f, expected) {
+			t.Fatalf("bad final exponentiation")
+		}
+	}
+}
+
+func TestPairingPrepared(t *testing.T) {
+	bls := NewEngine()
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	n := 4
+	P1, P2 := make([]*PointG1, n), make([]*PointG2, n)
+	for i := 0; i < n; i++ {
+		P1[i], P2[i] = g1.rand(), g2.rand()
+	}
+	for i := 0; i < n; i++ {
+		bls.AddPair(P1[i], P2[i])
+	}
+	expected := bls.Result()
+	// mix prepared and unprepared pairs
+	for i := 0; i < n; i++ {
+		if i%2 == 0 {
+			bls.AddPairPrepared(P1[i], bls.PrepareG2(P2[i]))
+		} else {
+			bls.AddPair(P1[i], P2[i])
+		}
+	}
+	if !gt.Equal(expected, bls.Result()) {
+		t.Fatalf("bad pairing with prepared points")
+	}
+	// serialization
+	for i := 0; i < n; i++ {
+		prepared, err := NewPreparedG2FromBytes(bls.PrepareG2(P2[i]).ToBytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		bls.AddPairPrepared(P1[i], prepared)
+	}
+	if !gt.Equal(expected, bls.Result()) {
+		t.Fatalf("bad pairing with deserialized prepared points")
+	}
+	// e(a * G1, G2) * e(-G1, a * G2) == 1
+	{
+		a := randScalar(q)
+		G1, G2 := g1.One(), g2.One()
+		g1.MulScalar(G1, G1, a)
+		g2.MulScalar(G2, G2, a)
+		bls.AddPairPrepared(G1, bls.PrepareG2(g2.One()))
+		bls.AddPairInv(g1.One(), G2)
+		if !bls.Check() {
+			t.Fatalf("bad pairing with prepared points")
+		}
+		bls.Reset()
+	}
+	// point at infinity
+	{
+		prepared := bls.PrepareG2(g2.Zero())
+		if !bytes.Equal(prepared.ToBytes(), make([]byte, preparedG2Size)) {
+			t.Fatalf("bad serialization of point at infinity")
+		}
+		prepared, err := NewPreparedG2FromBytes(prepared.ToBytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !gt.IsOne(bls.AddPairPrepared(g1.One(), prepared).Result()) {
+			t.Fatalf("pairing result is expected to be one")
+		}
+	}
+	// invalid inputs
+	{
+		in := bls.PrepareG2(g2.One()).ToBytes()
+		if _, err := NewPreparedG2FromBytes(in[1:]); err == nil {
+			t.Fatalf("short input is expected to fail")
+		}
+		in[0] = 0xff
+		if _, err := NewPreparedG2FromBytes(in); err == nil {
+			t.Fatalf("non canonical field element is expected to fail")
+		}
+	}
+}
+
+func TestPairingMillerLoopFinalExp(t *testing.T) {
+	bls := NewEngine()
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	n := 6
+	P1, P2 := make([]*PointG1, n), make([]*PointG2, n)
+	for i := 0; i < n; i++ {
+		P1[i], P2[i] = g1.rand(), g2.rand()
+		bls.AddPair(P1[i], P2[i])
+	}
+	expected := bls.Result()
+	// e(P0, Q0) * ... * e(Pn, Qn) == FinalExp(MillerLoop(P0, Q0 ... Pk, Qk) * MillerLoop(Pk+1, Qk+1 ... Pn, Qn))
+	for i := 0; i < n/2; i++ {
+		bls.AddPair(P1[i], P2[i])
+	}
+	f0 := bls.MillerLoop()
+	for i := n / 2; i < n; i++ {
+		bls.AddPair(P1[i], P2[i])
+	}
+	f1 := bls.MillerLoop()
+	f := gt.New()
+	gt.Mul(f, f0, f1)
+	if !gt.Equal(expected, bls.FinalExp(f)) {
+		t.Fatalf("bad pairing")
+	}
+	if gt.Equal(f, bls.FinalExp(f)) {
+		t.Fatalf("input of final exponentiation is not expected to be modified")
+	}
+	// empty
+	if !gt.IsOne(bls.MillerLoop()) {
+		t.Fatalf("empty miller loop should be one")
+	}
+	if !gt.IsOne(bls.FinalExp(gt.One())) {
+		t.Fatalf("final exponentiation of one should be one")
+	}
+}
+
+func TestPairingParallel(t *testing.T) {
+	bls := NewEngine()
+	g1, g2 := bls.G1, bls.G2
+	gt := bls.GT()
+	n := 9
+	P1, P2 := make([]*PointG1, n), make([]*PointG2, n)
+	for i := 0; i < n; i++ {
+		P1[i], P2[i] = g1.rand(), g2.rand()
+	}
+	for k := 1; k <= n; k++ {
+		for i := 0; i < k; i++ {
+			bls.AddPair(P1[i], P2[i])
+		}
+		expected := gt.ToBytes(bls.Result())
+		for _, workers := range []int{2, 3, 4, 16, 0} {
+			parallel := NewEngine().SetWorkers(workers)
+			for i := 0; i < k; i++ {
+				if i%3 == 0 {
+					parallel.AddPairPrepared(P1[i], parallel.PrepareG2(P2[i]))
+				} else {
+					parallel.AddPair(P1[i], P2[i])
+				}
+			}
+			if !bytes.Equal(expected, gt.ToBytes(parallel.Result())) {
+				t.Fatalf("parallel pairing doesn't match serial, pairs: %d, workers: %d", k, workers)
+			}
+		}
+	}
+}
+
+func TestPairingMillerLoopWorkers(t *testing.T) {
+	bls := NewEngine()
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	// enough pairs for pairing to run affine miller loop
+	n := affineMillerLoopThreshold + 2
+	P1, P2 := make([]*PointG1, n), make([]*PointG2, n)
+	for i := 0; i < n; i++ {
+		P1[i], P2[i] = g1.rand(), g2.rand()
+		bls.AddPair(P1[i], P2[i])
+	}
+	expected := gt.New()
+	bls.millerLoopProjective(expected)
+	bls.Reset()
+	for _, workers := range []int{1, 2, 3, 16} {
+		engine := NewEngine().SetWorkers(workers)
+		for i := 0; i < n; i++ {
+			engine.AddPair(P1[i], P2[i])
+		}
+		if !bytes.Equal(gt.ToBytes(expected), gt.ToBytes(engine.MillerLoop())) {
+			t.Fatalf("miller loop output depends on workers, workers: %d", workers)
+		}
+	}
+}
+
+func TestPairingAffineMillerLoop(t *testing.T) {
+	bls := NewEngine()
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	for _, n := range []int{1, 2, 5} {
+		for i := 0; i < n; i++ {
+			bls.AddPair(g1.rand(), g2.rand())
+		}
+		bls.AddPairPrepared(g1.rand(), bls.PrepareG2(g2.rand()))
+		f0, f1 := gt.New(), gt.New()
+		bls.millerLoopProjective(f0)
+		bls.millerLoopAffine(f1)
+		bls.Reset()
+		if !gt.Equal(bls.FinalExp(f0), bls.FinalExp(f1)) {
+			t.Fatalf("affine and projective miller loops don't match")
+		}
+	}
+}
+
+func TestPairingValidatingEngine(t *testing.T) {
+	bls := NewValidatingEngine()
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	// e(a * G1, G2) * e(-G1, a * G2) == 1
+	a := randScalar(q)
+	P1, P2 := g1.One(), g2.One()
+	g1.MulScalar(P1, P1, a)
+	g2.MulScalar(P2, P2, a)
+	if err := bls.AddPair(P1, g2.One()); err != nil {
+		t.Fatal(err)
+	}
+	if err := bls.AddPairInv(g1.One(), P2); err != nil {
+		t.Fatal(err)
+	}
+	if !bls.Check() {
+		t.Fatalf("bad pairing")
+	}
+	bls.Reset()
+	// cached points
+	n1, n2 := len(bls.g1Cache), len(bls.g2Cache)
+	for i := 0; i < 2; i++ {
+		prepared, err := bls.PrepareG2(P2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := bls.AddPairPrepared(P1, prepared); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(bls.g1Cache) != n1 || len(bls.g2Cache) != n2 {
+		t.Fatalf("validated points are expected to be cached")
+	}
+	bls.Reset()
+	// points at infinity
+	if err := bls.AddPair(g1.Zero(), g2.Zero()); err != nil {
+		t.Fatal(err)
+	}
+	if !gt.IsOne(bls.Result()) {
+		t.Fatalf("pairing result is expected to be one")
+	}
+	// not in correct subgroup
+	if err := bls.AddPair(g1.randCurvePoint(), g2.One()); err == nil {
+		t.Fatalf("g1 point not in correct subgroup is expected to be rejected")
+	}
+	if err := bls.AddPair(g1.One(), g2.randCurvePoint()); err == nil {
+		t.Fatalf("g2 point not in correct subgroup is expected to be rejected")
+	}
+	if _, err := bls.PrepareG2(g2.randCurvePoint()); err == nil {
+		t.Fatalf("g2 point not in correct subgroup is expected to be rejected")
+	}
+	// not on curve
+	P1 = g1.One()
+	P1[1].set(one())
+	if err := bls.AddPairInv(P1, g2.One()); err == nil {
+		t.Fatalf("g1 point not on curve is expected to be rejected")
+	}
+	P2 = g2.One()
+	P2[1][0].set(one())
+	if err := bls.AddPair(g1.One(), P2); err == nil {
+		t.Fatalf("g2 point not on curve is expected to be rejected")
+	}
+	if len(bls.pairs) != 0 {
+		t.Fatalf("rejected pairs are not expected to be added")
+	}
+}
+
+func TestPairingBatchVerifier(t *testing.T) {
+	bls := NewEngine()
+	g1, g2 := bls.G1, bls.G2
+	// e(a * G1, b * G2) * e(-ab * G1, G2) == 1
+	equation := func(valid bool) ([]*PointG1, []*PointG2) {
+		a, b := randScalar(q), randScalar(q)
+		c := new(big.Int).Mul(a, b)
+		if !valid {
+			c.Add(c, big.NewInt(1))
+		}
+		P0, Q0, P1 := g1.New(), g2.New(), g1.New()
+		g1.MulScalar(P0, g1.One(), a)
+		g2.MulScalar(Q0, g2.One(), b)
+		g1.MulScalar(P1, g1.One(), c)
+		g1.Neg(P1, P1)
+		return []*PointG1{P0, P1}, []*PointG2{Q0, g2.One()}
+	}
+	n := 12
+	for _, invalid := range [][]int{{}, {0}, {5}, {n - 1}, {2, 3}, {0, 6, 7, n - 1}} {
+		v := NewPairingBatchVerifier(mrand.New(mrand.NewSource(int64(len(invalid)))))
+		expected := make(map[int]bool)
+		for _, i := range invalid {
+			expected[i] = true
+		}
+		for i := 0; i < n; i++ {
+			P, Q := equation(!expected[i])
+			if err := v.Add(P, Q); err != nil {
+				t.Fatal(err)
+			}
+		}
+		failed, err := v.Verify()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(failed) != len(invalid) {
+			t.Fatalf("bad batch verification, expected %v, got %v", invalid, failed)
+		}
+		for i := range failed {
+			if failed[i] != invalid[i] {
+				t.Fatalf("bad batch verification, expected %v, got %v", invalid, failed)
+			}
+		}
+	}
+	// empty and degenerate equations
+	v := NewPairingBatchVerifier(nil)
+	if failed, err := v.Verify(); err != nil || len(failed) != 0 {
+		t.Fatalf("empty batch should be accepted")
+	}
+	if err := v.Add([]*PointG1{g1.Zero()}, []*PointG2{g2.One()}); err != nil {
+		t.Fatal(err)
+	}
+	if failed, err := v.Verify(); err != nil || len(failed) != 0 {
+		t.Fatalf("e(0, G2) == 1 should be accepted")
+	}
+	if err := v.Add([]*PointG1{g1.One()}, []*PointG2{}); err == nil {
+		t.Fatalf("length mismatch is expected to fail")
+	}
+	// points not in correct subgroup or not on curve
+	if err := v.Add([]*PointG1{g1.randCurvePoint()}, []*PointG2{g2.One()}); err == nil {
+		t.Fatalf("g1 point not in correct subgroup is expected to be rejected")
+	}
+	if err := v.Add([]*PointG1{g1.One()}, []*PointG2{g2.randCurvePoint()}); err == nil {
+		t.Fatalf("g2 point not in correct subgroup is expected to be rejected")
+	}
+	P := g1.One()
+	P[1].set(one())
+	if err := v.Add([]*PointG1{P}, []*PointG2{g2.One()}); err == nil {
+		t.Fatalf("g1 point not on curve is expected to be rejected")
+	}
+	if len(v.equations) != 1 {
+		t.Fatalf("rejected equations are not expected to be added")
+	}
+	// randomness failure
+	v = NewPairingBatchVerifier(bytes.NewReader(make([]byte, 20)))
+	for i := 0; i < 2; i++ {
+		P, Q := equation(true)
+		v.Add(P, Q)
+	}
+	if _, err := v.Verify(); err == nil {
+		t.Fatalf("short randomness is expected to fail")
+	}
+}
+
+func TestGTSubgroupCheck(t *testing.T) {
+	bls := NewEngine()
+	gt := bls.GT()
+	fp12 := gt.fp12
+	slowCheck := func(e *E) bool {
+		r := gt.New()
+		fp12.exp(r, e, q)
+		return gt.IsOne(r)
+	}
+	// maps to cyclotomic subgroup with f^((p^6 - 1) * (p^2 + 1))
+	easyPart := func(f *E) *E {
+		t := fp12.new()
+		fp12.inverse(t, f)
+		fp12.conjugate(f, f)
+		fp12.mul(f, f, t)
+		fp12.frobeniusMap(t, f, 2)
+		fp12.mul(f, f, t)
+		return f
+	}
+	if gt.IsInCyclotomicSubgroup(fp12.zero()) || gt.IsValid(fp12.zero()) {
+		t.Fatal("zero is not expected to be valid")
+	}
+	if !gt.IsValid(gt.One()) {
+		t.Fatal("one is expected to be valid")
+	}
+	for i := 0; i < fuz; i++ {
+		bls.AddPair(bls.G1.rand(), bls.G2.rand())
+		e := bls.Result()
+		if !gt.IsInCyclotomicSubgroup(e) || !gt.IsValid(e) {
+			t.Fatal("pairing result is expected to be valid")
+		}
+		f, _ := fp12.rand(rand.Reader)
+		if gt.IsInCyclotomicSubgroup(f) || gt.IsValid(f) {
+			t.Fatal("random element is not expected to be valid")
+		}
+		easyPart(f)
+		if !gt.IsInCyclotomicSubgroup(f) {
+			t.Fatal("element is expected to be in cyclotomic subgroup")
+		}
+		if gt.IsValid(f) != slowCheck(f) {
+			t.Fatal("bad subgroup check")
+		}
+		if gt.IsValid(f) {
+			t.Fatal("element is not expected to be in subgroup")
+		}
+	}
+}
+
+func TestGTSerializationCompressed(t *testing.T) {
+	bls := NewEngine()
+	gt := bls.GT()
+	for i := 0; i < fuz; i++ {
+		bls.AddPair(bls.G1.rand(), bls.G2.rand())
+		e := bls.Result()
+		in := gt.ToCompressed(e)
+		if len(in) != 288 {
+			t.Fatal("bad compressed encoding length")
+		}
+		r, err := gt.FromCompressed(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !gt.Equal(e, r) {
+			t.Fatal("bad compressed serialization")
+		}
+		// random encodings are not expected to be in subgroup
+		c, _ := gt.fp12.fp6.rand(rand.Reader)
+		if _, err := gt.FromCompressed(gt.fp12.fp6.toBytes(c)); err == nil {
+			t.Fatal("random encoding is expected to fail")
+		}
+	}
+	r, err := gt.FromCompressed(gt.ToCompressed(gt.One()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gt.IsOne(r) {
+		t.Fatal("bad compressed serialization of one")
+	}
+	if _, err := gt.FromCompressed(make([]byte, 287)); err == nil {
+		t.Fatal("short input is expected to fail")
+	}
+	if _, err := gt.FromCompressed(bytes.Repeat([]byte{0xff}, 288)); err == nil {
+		t.Fatal("non canonical input is expected to fail")
+	}
+}
+
+func TestGTExp(t *testing.T) {
+	bls := NewEngine()
+	gt := bls.GT()
+	bls.AddPair(bls.G1.rand(), bls.G2.rand())
+	e := bls.Result()
+	qMinusOne := new(big.Int).Sub(q, big.NewInt(1))
+	scalars := []*big.Int{big.NewInt(0), big.NewInt(1), big.NewInt(2), x, q, qMinusOne, new(big.Int).Lsh(q, 10)}
+	for i := 0; i < fuz; i++ {
+		scalars = append(scalars, randScalar(q), new(big.Int).Neg(randScalar(q)))
+	}
+	for _, s := range scalars {
+		expected, r := gt.New(), gt.New()
+		gt.fp12.exp(expected, e, new(big.Int).Mod(s, q))
+		gt.ExpInGroup(r, e, s)
+		if !gt.Equal(r, expected) {
+			t.Fatalf("bad exponentiation, scalar %x", s)
+		}
+	}
+	r := gt.New()
+	gt.InverseUnitary(r, e)
+	gt.Mul(r, r, e)
+	if !gt.IsOne(r) {
+		t.Fatal("bad inversion")
+	}
+	// elements out of target group such as miller loop outputs
+	bls.AddPair(bls.G1.rand(), bls.G2.rand())
+	f := bls.MillerLoop()
+	a, _ := gt.fp12.rand(rand.Reader)
+	for _, e := range []*E{e, f, a} {
+		for _, s := range scalars {
+			expected, r := gt.New(), gt.New()
+			gt.fp12.exp(expected, e, s)
+			gt.Exp(r, e, s)
+			if !gt.Equal(r, expected) {
+				t.Fatalf("bad exponentiation, scalar %x", s)
+			}
+		}
+		gt.Inverse(r, e)
+		gt.Mul(r, r, e)
+		if !gt.IsOne(r) {
+			t.Fatal("bad inversion")
+		}
+	}
+}
+
+func TestGTMultiExp(t *testing.T) {
+	bls := NewEngine()
+	gt := bls.GT()
+	for _, n := range []int{0, 1, 2, 5, 40} {
+		elems, powers := make([]*E, n), make([]*big.Int, n)
+		expected := gt.One()
+		for i := 0; i < n; i++ {
+			bls.AddPair(bls.G1.rand(), bls.G2.One())
+			elems[i] = bls.Result()
+			powers[i] = randScalar(q)
+			if i == 0 {
+				powers[i] = big.NewInt(0)
+			}
+			r := gt.New()
+			gt.fp12.exp(r, elems[i], powers[i])
+			gt.Mul(expected, expected, r)
+		}
+		r := gt.New()
+		if _, err := gt.MultiExp(r, elems, powers); err != nil {
+			t.Fatal(err)
+		}
+		if !gt.Equal(r, expected) {
+			t.Fatalf("bad multi exponentiation, n %d", n)
+		}
+	}
+	if _, err := gt.MultiExp(gt.New(), []*E{gt.One()}, nil); err == nil {
+		t.Fatal("length mismatch is expected to fail")
+	}
+}
+
+func TestGTExpPrepared(t *testing.T) {
+	bls := NewEngine()
+	gt := bls.GT()
+	bls.AddPair(bls.G1.rand(), bls.G2.rand())
+	e := bls.Result()
+	p := gt.PrepareBase(e)
+	scalars := []*big.Int{big.NewInt(0), big.NewInt(1), x, q, new(big.Int).Sub(q, big.NewInt(1))}
+	for i := 0; i < fuz; i++ {
+		scalars = append(scalars, randScalar(q), new(big.Int).Neg(randScalar(q)))
+	}
+	for _, s := range scalars {
+		expected, r := gt.New(), gt.New()
+		gt.ExpInGroup(expected, e, s)
+		gt.ExpPrepared(r, p, s)
+		if !gt.Equal(r, expected) {
+			t.Fatalf("bad prepared exponentiation, scalar %x", s)
+		}
+	}
+}
+
 func TestPairingEmpty(t *testing.T) {
 	bls := NewEngine()
 	GT := bls.GT()
@@ -229,3 +771,131 @@ func BenchmarkPairing(t *testing.B) {
 	}
 	_ = e
 }
+
+func BenchmarkPairingPrepared(t *testing.B) {
+	bls := NewEngine()
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	prepared := bls.PrepareG2(g2.One())
+	bls.AddPairPrepared(g1.One(), prepared)
+	e := gt.New()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		e = bls.calculate()
+	}
+	_ = e
+}
+
+func BenchmarkPairingParallel(t *testing.B) {
+	bls := NewEngine().SetWorkers(0)
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	for i := 0; i < 64; i++ {
+		bls.AddPair(g1.One(), g2.One())
+	}
+	e := gt.New()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		e = bls.calculate()
+	}
+	_ = e
+}
+
+func BenchmarkPairingMany(t *testing.B) {
+	bls := NewEngine()
+	g1, g2, gt := bls.G1, bls.G2, bls.GT()
+	for i := 0; i < 64; i++ {
+		bls.AddPair(g1.rand(), g2.rand())
+	}
+	e := gt.New()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		e = bls.calculate()
+	}
+	_ = e
+}
+
+func BenchmarkPairingBatchVerifier(t *testing.B) {
+	bls := NewEngine()
+	g1, g2 := bls.G1, bls.G2
+	v := NewPairingBatchVerifier(nil)
+	for i := 0; i < 64; i++ {
+		// e(a * G1, G2) * e(-G1, a * G2) == 1
+		a := randScalar(q)
+		P, Q := g1.New(), g2.New()
+		g1.MulScalar(P, g1.One(), a)
+		g2.MulScalar(Q, g2.One(), a)
+		v.Add([]*PointG1{P, g1.Neg(g1.New(), g1.One())}, []*PointG2{g2.One(), Q})
+	}
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		if failed, _ := v.Verify(); len(failed) != 0 {
+			t.Fatal("bad batch verification")
+		}
+	}
+}
+
+func BenchmarkGTSubgroupCheck(t *testing.B) {
+	bls := NewEngine()
+	bls.AddPair(bls.G1.One(), bls.G2.One())
+	e := bls.Result()
+	gt := bls.GT()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		gt.IsValid(e)
+	}
+}
+
+func BenchmarkGTExp(t *testing.B) {
+	bls := NewEngine()
+	bls.AddPair(bls.G1.One(), bls.G2.One())
+	e := bls.Result()
+	gt := bls.GT()
+	s := randScalar(q)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		gt.Exp(e, e, s)
+	}
+}
+
+func BenchmarkGTExpInGroup(t *testing.B) {
+	bls := NewEngine()
+	bls.AddPair(bls.G1.One(), bls.G2.One())
+	e := bls.Result()
+	gt := bls.GT()
+	s := randScalar(q)
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		gt.ExpInGroup(e, e, s)
+	}
+}
+
+func BenchmarkGTMultiExp(t *testing.B) {
+	bls := NewEngine()
+	gt := bls.GT()
+	for _, n := range []int{2, 16, 128} {
+		elems, powers := make([]*E, n), make([]*big.Int, n)
+		for i := 0; i < n; i++ {
+			bls.AddPair(bls.G1.rand(), bls.G2.One())
+			elems[i] = bls.Result()
+			powers[i] = randScalar(q)
+		}
+		r := gt.New()
+		t.Run(fmt.Sprintf("%d", n), func(t *testing.B) {
+			for i := 0; i < t.N; i++ {
+				gt.MultiExp(r, elems, powers)
+			}
+		})
+	}
+}
+
+func BenchmarkGTExpPrepared(t *testing.B) {
+	bls := NewEngine()
+	bls.AddPair(bls.G1.One(), bls.G2.One())
+	gt := bls.GT()
+	p := gt.PrepareBase(bls.Result())
+	s := randScalar(q)
+	r := gt.New()
+	t.ResetTimer()
+	for i := 0; i < t.N; i++ {
+		gt.ExpPrepared(r, p, s)
+	}
+}
@@ -0,0 +1,144 @@
+package bls12381
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Suite describes a hashing to curve suite of BLS12-381 as named in RFC 9380.
+// A suite identifier is in form of CURVE_ID || "_" || HASH_ID || "_" || MAP_ID || "_" || ENC_VAR || "_"
+// for example BLS12381G1_XMD:SHA-256_SSWU_RO_.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-8.10
+// Suites are expected to be created with NewSuite, others are rejected by hashing functions.
+type Suite struct {
+	// ID is the suite identifier.
+	ID string
+	// Group is 1 for G1 and 2 for G2.
+	Group int
+	// Expansion is either XMD or XOF.
+	Expansion string
+	// Hash is the name of the hash function such as SHA-256 or SHAKE-128.
+	Hash string
+	// RandomOracle is true for hash_to_curve (RO) and false for encode_to_curve (NU) suites.
+	RandomOracle bool
+	expand       Expander
+	newStream    func(domain []byte) (messageStream, error)
+}
+
+var suiteCurveIDs = map[string]int{
+	"BLS12381G1": 1,
+	"BLS12381G2": 2,
+}
+
+var suiteXMDHashes = map[string]func() hash.Hash{
+	"SHA-256":  sha256.New,
+	"SHA-384":  sha512.New384,
+	"SHA-512":  sha512.New,
+	"SHA3-256": sha3.New256,
+	"SHA3-384": sha3.New384,
+	"SHA3-512": sha3.New512,
+}
+
+var suiteXOFHashes = map[string]struct {
+	newHash func() sha3.ShakeHash
+	k       int
+}{
+	"SHAKE-128": {sha3.NewShake128, 128},
+	"SHAKE-256": {sha3.NewShake256, 256},
+}
+
+// NewSuite parses given suite identifier and returns the suite.
+// Only simplified SWU map is supported and unknown curve, hash or encoding identifiers are rejected.
+func NewSuite(id string) (*Suite, error) {
+	parts := strings.Split(id, "_")
+	// trailing underscore leaves an empty part at the end
+	if len(parts) != 5 || parts[4] != "" {
+		return nil, fmt.Errorf("malformed suite identifier %q", id)
+	}
+	s := &Suite{ID: id}
+	group, ok := suiteCurveIDs[parts[0]]
+	if !ok {
+		return nil, fmt.Errorf("unknown curve identifier in suite %q", id)
+	}
+	s.Group = group
+	hashID := strings.SplitN(parts[1], ":", 2)
+	if len(hashID) != 2 {
+		return nil, fmt.Errorf("malformed hash identifier in suite %q", id)
+	}
+	s.Expansion, s.Hash = hashID[0], hashID[1]
+	switch s.Expansion {
+	case "XMD":
+		newHash, ok := suiteXMDHashes[s.Hash]
+		if !ok {
+			return nil, fmt.Errorf("unknown hash function in suite %q", id)
+		}
+		s.expand = ExpandMsgXMD(newHash)
+		s.newStream = func(domain []byte) (messageStream, error) {
+			stream, err := newXMDStream(newHash, domain)
+			if err != nil {
+				return nil, err
+			}
+			return stream, nil
+		}
+	case "XOF":
+		xof, ok := suiteXOFHashes[s.Hash]
+		if !ok {
+			return nil, fmt.Errorf("unknown hash function in suite %q", id)
+		}
+		s.expand = ExpandMsgXOF(xof.newHash, xof.k)
+		s.newStream = func(domain []byte) (messageStream, error) {
+			return newXOFStream(xof.newHash, xof.k, domain), nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown expansion in suite %q", id)
+	}
+	if parts[2] != "SSWU" {
+		return nil, fmt.Errorf("unknown map in suite %q", id)
+	}
+	switch parts[3] {
+	case "RO":
+		s.RandomOracle = true
+	case "NU":
+		s.RandomOracle = false
+	default:
+		return nil, fmt.Errorf("unknown encoding in suite %q", id)
+	}
+	return s, nil
+}
+
+// check returns an error if the suite is nil, is not created with NewSuite or is not defined for given group.
+func (s *Suite) check(group int) error {
+	if s == nil {
+		return fmt.Errorf("suite is nil")
+	}
+	if s.expand == nil || s.newStream == nil {
+		return fmt.Errorf("suite %s is not created with NewSuite", s.ID)
+	}
+	if s.Group != group {
+		return fmt.Errorf("suite %s is not defined for G%d", s.ID, group)
+	}
+	return nil
+}
+
+// DST builds a domain separation tag for given application tag, protocol version and cipher suite
+// following the recommendation of the RFC, that is tag-Vxx-CSyy-with-suite.
+// For example application QUUX, version 1 and cipher suite 2 gives QUUX-V01-CS02-with-BLS12381G1_XMD:SHA-256_SSWU_RO_.
+// https://www.rfc-editor.org/rfc/rfc9380.html#section-3.1
+func (s *Suite) DST(application string, version, cipherSuite int) []byte {
+	return []byte(fmt.Sprintf("%s-V%02d-CS%02d-with-%s", application, version, cipherSuite, s.ID))
+}
+
+// Expander returns the message expansion function of the suite.
+func (s *Suite) Expander() Expander {
+	return s.expand
+}
+
+// String returns the suite identifier.
+func (s *Suite) String() string {
+	return s.ID
+}
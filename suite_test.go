@@ -0,0 +1,151 @@
+package bls12381
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"testing"
+)
+
+func TestSuiteParse(t *testing.T) {
+	for _, v := range []struct {
+		id           string
+		group        int
+		expansion    string
+		hash         string
+		randomOracle bool
+	}{
+		{SuiteG1XMDSHA256SSWURO, 1, "XMD", "SHA-256", true},
+		{SuiteG1XMDSHA256SSWUNU, 1, "XMD", "SHA-256", false},
+		{SuiteG2XMDSHA256SSWURO, 2, "XMD", "SHA-256", true},
+		{SuiteG2XMDSHA256SSWUNU, 2, "XMD", "SHA-256", false},
+		{SuiteG1XOFSHAKE128SSWURO, 1, "XOF", "SHAKE-128", true},
+		{SuiteG1XOFSHAKE256SSWUNU, 1, "XOF", "SHAKE-256", false},
+		{SuiteG2XOFSHAKE128SSWUNU, 2, "XOF", "SHAKE-128", false},
+		{SuiteG2XOFSHAKE256SSWURO, 2, "XOF", "SHAKE-256", true},
+		{"BLS12381G1_XMD:SHA-512_SSWU_RO_", 1, "XMD", "SHA-512", true},
+	} {
+		s, err := NewSuite(v.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Group != v.group || s.Expansion != v.expansion || s.Hash != v.hash || s.RandomOracle != v.randomOracle {
+			t.Fatalf("bad suite parsing for %s", v.id)
+		}
+	}
+	for _, id := range []string{
+		"",
+		"BLS12381G1_XMD:SHA-256_SSWU_RO",
+		"BLS12381G3_XMD:SHA-256_SSWU_RO_",
+		"BLS12381G1_XMD:SHA-1_SSWU_RO_",
+		"BLS12381G1_XMD:SHAKE-128_SSWU_RO_",
+		"BLS12381G1_XOF:SHA-256_SSWU_RO_",
+		"BLS12381G1_SHA-256_SSWU_RO_",
+		"BLS12381G1_XMD:SHA-256_SVDW_RO_",
+		"BLS12381G1_XMD:SHA-256_SSWU_XX_",
+		"BLS12381G1_XMD:SHA-256_SSWU_RO_extra_",
+	} {
+		if _, err := NewSuite(id); err == nil {
+			t.Fatalf("suite %q is expected to be rejected", id)
+		}
+	}
+}
+
+func TestSuiteDST(t *testing.T) {
+	s, err := NewSuite(SuiteG2XMDSHA256SSWURO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s.DST("QUUX", 1, 2), []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_")) {
+		t.Fatal("bad domain separation tag")
+	}
+}
+
+func TestSuiteHashToCurve(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	msg := []byte("abc")
+	g1Hashes := map[string]func(msg, domain []byte) (*PointG1, error){
+		SuiteG1XMDSHA256SSWURO:   g1.HashToCurve,
+		SuiteG1XMDSHA256SSWUNU:   g1.EncodeToCurve,
+		SuiteG1XOFSHAKE128SSWURO: g1.HashToCurveSHAKE128,
+		SuiteG1XOFSHAKE256SSWUNU: g1.EncodeToCurveSHAKE256,
+		"BLS12381G1_XMD:SHA-512_SSWU_RO_": func(msg, domain []byte) (*PointG1, error) {
+			return g1.HashToCurveWith(sha512.New, msg, domain)
+		},
+	}
+	for id, hashFn := range g1Hashes {
+		s, err := NewSuite(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		domain := s.DST("QUUX", 1, 2)
+		p0, err := g1.HashToCurveWithSuite(s, msg, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p1, err := hashFn(msg, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g1.Equal(p0, p1) {
+			t.Fatalf("bad hash to curve with suite %s", id)
+		}
+		if _, err := g2.HashToCurveWithSuite(s, msg, domain); err == nil {
+			t.Fatalf("G1 suite %s is expected to be rejected by G2", id)
+		}
+	}
+	g2Hashes := map[string]func(msg, domain []byte) (*PointG2, error){
+		SuiteG2XMDSHA256SSWURO:   g2.HashToCurve,
+		SuiteG2XMDSHA256SSWUNU:   g2.EncodeToCurve,
+		SuiteG2XOFSHAKE128SSWUNU: g2.EncodeToCurveSHAKE128,
+		SuiteG2XOFSHAKE256SSWURO: g2.HashToCurveSHAKE256,
+	}
+	for id, hashFn := range g2Hashes {
+		s, err := NewSuite(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		domain := s.DST("QUUX", 1, 2)
+		p0, err := g2.HashToCurveWithSuite(s, msg, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p1, err := hashFn(msg, domain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !g2.Equal(p0, p1) {
+			t.Fatalf("bad hash to curve with suite %s", id)
+		}
+		if _, err := g1.HashToCurveWithSuite(s, msg, domain); err == nil {
+			t.Fatalf("G2 suite %s is expected to be rejected by G1", id)
+		}
+	}
+}
+
+func TestSuiteInvalid(t *testing.T) {
+	g1, g2 := NewG1(), NewG2()
+	msg, domain := []byte("abc"), []byte("QUUX-V01-CS02")
+	for _, s := range []*Suite{nil, {ID: SuiteG1XMDSHA256SSWURO, Group: 1, RandomOracle: true}} {
+		if _, err := g1.HashToCurveWithSuite(s, msg, domain); err == nil {
+			t.Fatal("suite is expected to be rejected by G1")
+		}
+		if s == nil {
+			// nil suite selects the default one for hashers
+			continue
+		}
+		if _, err := g1.NewHasher(s, domain); err == nil {
+			t.Fatal("suite is expected to be rejected by G1 hasher")
+		}
+	}
+	for _, s := range []*Suite{nil, {ID: SuiteG2XMDSHA256SSWURO, Group: 2, RandomOracle: true}} {
+		if _, err := g2.HashToCurveWithSuite(s, msg, domain); err == nil {
+			t.Fatal("suite is expected to be rejected by G2")
+		}
+		if s == nil {
+			continue
+		}
+		if _, err := g2.NewHasher(s, domain); err == nil {
+			t.Fatal("suite is expected to be rejected by G2 hasher")
+		}
+	}
+}
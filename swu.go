@@ -1,7 +1,10 @@
 package bls12381
 
+import "math/big"
+
 // swuMapG1 is implementation of Simplified Shallue-van de Woestijne-Ulas Method
-// follows the implmentation at draft-irtf-cfrg-hash-to-curve-06.
+// follows the implmentation at RFC 9380 section 6.6.2.
+// It branches on the input and is kept as a reference for swuMapG1CT.
 func swuMapG1(u *fe) (*fe, *fe) {
 	var params = swuParamsForG1
 	var tv [4]*fe
@@ -17,7 +20,7 @@ func swuMapG1(u *fe) (*fe, *fe) {
 	e1 := isZero(x1)
 	add(x1, x1, one())
 	if e1 {
-		x1.set(params.zInv)
+		x1.set(params.minusZInv)
 	}
 	mul(x1, x1, params.minusBOverA)
 	gx1 := new(fe)
@@ -51,8 +54,81 @@ func swuMapG1(u *fe) (*fe, *fe) {
 	return x, y
 }
 
+// swuMapG1Inverse returns all field elements that swuMapG1 maps to point (x, y) on the isogenous curve.
+// With t = Z * u^2 the map outputs x1 = -B / A * (1 + 1 / (t^2 + t)) if g(x1) is square and x2 = t * x1 otherwise,
+// so with w = -A / B * x candidates of t are roots of t^2 + t - 1 / (w - 1) and t^2 + (1 - w) * t + 1 - w
+// and 0, -1 of the exceptional case. Sign of u is the sign of y, so there are at most six preimages.
+// Each candidate is checked with the forward map. It is not constant time.
+func swuMapG1Inverse(x, y *fe) []*fe {
+	params := swuParamsForG1
+	w, c, b, t := new(fe), new(fe), new(fe), new(fe)
+	inverse(w, params.minusBOverA)
+	mul(w, w, x)
+	var ts []*fe
+	// t^2 + t - 1 / (w - 1) = 0
+	sub(c, w, one())
+	if !c.isZero() {
+		inverse(c, c)
+		neg(c, c)
+		ts = append(ts, solveQuadratic(one(), c)...)
+	}
+	// t^2 + (1 - w) * t + 1 - w = 0
+	sub(b, one(), w)
+	ts = append(ts, solveQuadratic(b, b)...)
+	neg(t, one())
+	ts = append(ts, zero(), t)
+	var preimages []*fe
+	for _, t := range ts {
+		// u^2 = t / Z
+		u := new(fe)
+		mul(t, t, params.minusZInv)
+		neg(t, t)
+		if !sqrt(u, t) {
+			continue
+		}
+		if sgn0(u) != sgn0(y) {
+			neg(u, u)
+		}
+		duplicate := false
+		for _, v := range preimages {
+			duplicate = duplicate || equal(u, v)
+		}
+		if duplicate {
+			continue
+		}
+		x0, y0 := swuMapG1CT(u)
+		if equal(x0, x) && equal(y0, y) {
+			preimages = append(preimages, u)
+		}
+	}
+	return preimages
+}
+
+// solveQuadratic returns distinct roots of t^2 + b * t + c in base field.
+func solveQuadratic(b, c *fe) []*fe {
+	d, t := new(fe), new(fe)
+	square(d, b)
+	double(t, c)
+	double(t, t)
+	sub(d, d, t)
+	if !sqrt(d, d) {
+		return nil
+	}
+	t0, t1 := new(fe), new(fe)
+	sub(t0, d, b)
+	mul(t0, t0, twoInv)
+	if d.isZero() {
+		return []*fe{t0}
+	}
+	neg(t1, d)
+	sub(t1, t1, b)
+	mul(t1, t1, twoInv)
+	return []*fe{t0, t1}
+}
+
 // swuMapG2 is implementation of Simplified Shallue-van de Woestijne-Ulas Method
-// defined at draft-irtf-cfrg-hash-to-curve-06.
+// defined at RFC 9380 section 6.6.2.
+// It branches on the input and is kept as a reference for swuMapG2CT.
 func swuMapG2(e *fp2, u *fe2) (*fe2, *fe2) {
 	if e == nil {
 		e = newFp2()
@@ -71,7 +147,7 @@ func swuMapG2(e *fp2, u *fe2) (*fe2, *fe2) {
 	e1 := e.isZero(x1)
 	e.add(x1, x1, e.one())
 	if e1 {
-		e.copy(x1, params.zInv)
+		e.copy(x1, params.minusZInv)
 	}
 	e.mul(x1, x1, params.minusBOverA)
 	gx1 := e.new()
@@ -115,26 +191,215 @@ func swuMapG2(e *fp2, u *fe2) (*fe2, *fe2) {
 	return x, y
 }
 
+// swuMapG1CT is the straight line implementation of Simplified Shallue-van de Woestijne-Ulas Method
+// given at RFC 9380 appendix F.2. Unlike swuMapG1 it does not branch on the input or
+// on intermediate values and it uses Fermat inversion, so that it can be used with secret inputs.
+// It returns the same point with swuMapG1.
+// Note that field arithmetic is branch free only on amd64 builds without the generic tag, with or without ADX.
+// Native go arithmetic used on other platforms and with the generic tag branches in add, sub, neg and reduction.
+func swuMapG1CT(u *fe) (*fe, *fe) {
+	x, xDen, y := swuMapG1Fraction(u)
+	inverseCT(xDen, xDen)
+	mul(x, x, xDen)
+	return x, y
+}
+
+// swuMapG1Fraction is swuMapG1CT where x coordinate is returned as a fraction xNum / xDen
+// so that the caller can choose how to invert the denominator.
+func swuMapG1Fraction(u *fe) (*fe, *fe, *fe) {
+	params := swuParamsForG1
+	var tv [7]*fe
+	for i := 0; i < 7; i++ {
+		tv[i] = new(fe)
+	}
+	// tv1 = Z * u^2
+	square(tv[1], u)
+	mul(tv[1], tv[1], params.z)
+	// tv2 = tv1^2 + tv1
+	square(tv[2], tv[1])
+	add(tv[2], tv[2], tv[1])
+	// tv3 = B * (tv2 + 1)
+	add(tv[3], tv[2], one())
+	mul(tv[3], tv[3], params.b)
+	// tv4 = A * CMOV(Z, -tv2, tv2 != 0)
+	sub(tv[4], zero(), tv[2])
+	cmov(tv[4], tv[4], params.z, isZeroCT(tv[2]))
+	mul(tv[4], tv[4], params.a)
+	// tv2 = (tv3^2 + A * tv4^2) * tv3 + B * tv4^3
+	// tv6 = tv4^3
+	square(tv[2], tv[3])
+	square(tv[6], tv[4])
+	mul(tv[5], params.a, tv[6])
+	add(tv[2], tv[2], tv[5])
+	mul(tv[2], tv[2], tv[3])
+	mul(tv[6], tv[6], tv[4])
+	mul(tv[5], params.b, tv[6])
+	add(tv[2], tv[2], tv[5])
+	x, y, y1 := new(fe), new(fe), new(fe)
+	mul(x, tv[1], tv[3])
+	isGx1Square := sqrtRatioG1(y1, tv[2], tv[6])
+	mul(y, tv[1], u)
+	mul(y, y, y1)
+	cmov(x, x, tv[3], isGx1Square)
+	cmov(y, y, y1, isGx1Square)
+	// y = CMOV(-y, y, sgn0(u) == sgn0(y))
+	e1 := 1 ^ sgn0(u) ^ sgn0(y)
+	sub(y1, zero(), y)
+	cmov(y, y1, y, e1)
+	// x = x / tv4
+	return x, tv[4], y
+}
+
+// sqrtRatioG1 sets y to sqrt(u / v) if u / v is square and to sqrt(Z * u / v) otherwise
+// where Z is the non-square of the map. It returns 1 if u / v is square and 0 otherwise.
+// Implementation follows optimized sqrt_ratio for q = 3 mod 4 at RFC 9380 appendix F.2.1.2.
+func sqrtRatioG1(y, u, v *fe) uint64 {
+	tv1, tv2, tv3, y1, y2 := new(fe), new(fe), new(fe), new(fe), new(fe)
+	square(tv1, v)
+	mul(tv2, u, v)
+	mul(tv1, tv1, tv2)
+	exp(y1, tv1, pMinus3Over4)
+	mul(y1, y1, tv2)
+	mul(y2, y1, swuParamsForG1.sqrtMinusZ)
+	square(tv3, y1)
+	mul(tv3, tv3, v)
+	isQR := equalCT(tv3, u)
+	cmov(y, y2, y1, isQR)
+	return isQR
+}
+
+// swuMapG2CT is the straight line implementation of Simplified Shallue-van de Woestijne-Ulas Method
+// given at RFC 9380 appendix F.2. Unlike swuMapG2 it does not branch on the input or
+// on intermediate values and it uses Fermat inversion, so that it can be used with secret inputs.
+// It returns the same point with swuMapG2.
+func swuMapG2CT(e *fp2, u *fe2) (*fe2, *fe2) {
+	if e == nil {
+		e = newFp2()
+	}
+	x, xDen, y := swuMapG2Fraction(e, u)
+	e.inverseCT(xDen, xDen)
+	e.mul(x, x, xDen)
+	return x, y
+}
+
+// swuMapG2Fraction is swuMapG2CT where x coordinate is returned as a fraction xNum / xDen
+// so that the caller can choose how to invert the denominator.
+func swuMapG2Fraction(e *fp2, u *fe2) (*fe2, *fe2, *fe2) {
+	params := swuParamsForG2
+	var tv [7]*fe2
+	for i := 0; i < 7; i++ {
+		tv[i] = e.new()
+	}
+	// tv1 = Z * u^2
+	e.square(tv[1], u)
+	e.mul(tv[1], tv[1], params.z)
+	// tv2 = tv1^2 + tv1
+	e.square(tv[2], tv[1])
+	e.add(tv[2], tv[2], tv[1])
+	// tv3 = B * (tv2 + 1)
+	e.add(tv[3], tv[2], e.one())
+	e.mul(tv[3], tv[3], params.b)
+	// tv4 = A * CMOV(Z, -tv2, tv2 != 0)
+	e.sub(tv[4], e.zero(), tv[2])
+	e.cmov(tv[4], tv[4], params.z, e.isZeroCT(tv[2]))
+	e.mul(tv[4], tv[4], params.a)
+	// tv2 = (tv3^2 + A * tv4^2) * tv3 + B * tv4^3
+	// tv6 = tv4^3
+	e.square(tv[2], tv[3])
+	e.square(tv[6], tv[4])
+	e.mul(tv[5], params.a, tv[6])
+	e.add(tv[2], tv[2], tv[5])
+	e.mul(tv[2], tv[2], tv[3])
+	e.mul(tv[6], tv[6], tv[4])
+	e.mul(tv[5], params.b, tv[6])
+	e.add(tv[2], tv[2], tv[5])
+	x, y, y1 := e.new(), e.new(), e.new()
+	e.mul(x, tv[1], tv[3])
+	isGx1Square := sqrtRatioG2(e, y1, tv[2], tv[6])
+	e.mul(y, tv[1], u)
+	e.mul(y, y, y1)
+	e.cmov(x, x, tv[3], isGx1Square)
+	e.cmov(y, y, y1, isGx1Square)
+	// y = CMOV(-y, y, sgn0(u) == sgn0(y))
+	e1 := 1 ^ e.sgn0(u) ^ e.sgn0(y)
+	e.sub(y1, e.zero(), y)
+	e.cmov(y, y1, y, e1)
+	// x = x / tv4
+	return x, tv[4], y
+}
+
+// sqrtRatioG2 sets y to sqrt(u / v) if u / v is square and to sqrt(Z * u / v) otherwise
+// where Z is the non-square of the map. It returns 1 if u / v is square and 0 otherwise.
+// Implementation follows generic sqrt_ratio at RFC 9380 appendix F.2.1.1
+// where 2^3 is the largest power of two that divides q^2 - 1, so c1 = 3, c4 = 7 and c5 = 4.
+func sqrtRatioG2(e *fp2, y, u, v *fe2) uint64 {
+	params := swuParamsForG2
+	tv1, tv2, tv3, tv4, tv5 := e.new(), e.new(), e.new(), e.new(), e.new()
+	e.copy(tv1, params.zPowC2)
+	// tv2 = v^7
+	e.square(tv2, v)
+	e.mul(tv2, tv2, v)
+	e.square(tv2, tv2)
+	e.mul(tv2, tv2, v)
+	e.square(tv3, tv2)
+	e.mul(tv3, tv3, v)
+	e.mul(tv5, u, tv3)
+	e.exp(tv5, tv5, params.sqrtRatioC3)
+	e.mul(tv5, tv5, tv2)
+	e.mul(tv2, tv5, v)
+	e.mul(tv3, tv5, u)
+	e.mul(tv4, tv3, tv2)
+	// tv5 = tv4^4
+	e.square(tv5, tv4)
+	e.square(tv5, tv5)
+	isQR := e.equalCT(tv5, e.one())
+	e.mul(tv2, tv3, params.zPowC2Plus1Over2)
+	e.mul(tv5, tv4, tv1)
+	e.cmov(tv3, tv2, tv3, isQR)
+	e.cmov(tv4, tv5, tv4, isQR)
+	for k := 3; k >= 2; k-- {
+		// tv5 = tv4^(2^(k-2))
+		e.copy(tv5, tv4)
+		for i := 0; i < k-2; i++ {
+			e.square(tv5, tv5)
+		}
+		e1 := e.equalCT(tv5, e.one())
+		e.mul(tv2, tv3, tv1)
+		e.square(tv1, tv1)
+		e.mul(tv5, tv4, tv1)
+		e.cmov(tv3, tv2, tv3, e1)
+		e.cmov(tv4, tv5, tv4, e1)
+	}
+	e.copy(y, tv3)
+	return isQR
+}
+
 var swuParamsForG1 = struct {
 	z           *fe
-	zInv        *fe
+	minusZInv   *fe
 	a           *fe
 	b           *fe
 	minusBOverA *fe
+	sqrtMinusZ  *fe
 }{
 	a:           &fe{3415322872136444497, 9675504606121301699, 13284745414851768802, 2873609449387478652, 2897906769629812789, 1536947672689614213},
 	b:           &fe{18129637713272545760, 11144507692959411567, 10108153527111632324, 9745270364868568433, 14587922135379007624, 469008097655535723},
 	z:           &fe{9830232086645309404, 1112389714365644829, 8603885298299447491, 11361495444721768256, 5788602283869803809, 543934104870762216},
-	zInv:        &fe{1047701040585522704, 6568704757426767313, 7461573184509654906, 5499015922318795030, 11226104418450030905, 1048548528059189658},
+	minusZInv:   &fe{1047701040585522704, 6568704757426767313, 7461573184509654906, 5499015922318795030, 11226104418450030905, 1048548528059189658},
 	minusBOverA: &fe{370847444534405118, 4269648997187665026, 1978763176675559811, 2677363437243537255, 11096866317338941469, 683609622716391635},
+	sqrtMinusZ:  &fe{17544630987809824292, 17306709551153317753, 8299808889594647786, 5930295261504720397, 675038575008112577, 167386374569371918},
 }
 
 var swuParamsForG2 = struct {
 	z           *fe2
-	zInv        *fe2
+	minusZInv   *fe2
 	a           *fe2
 	b           *fe2
 	minusBOverA *fe2
+	// constants of sqrt_ratio, c3 = (c2 - 1) / 2 where c2 = (q^2 - 1) / 2^3
+	sqrtRatioC3      *big.Int
+	zPowC2           *fe2
+	zPowC2Plus1Over2 *fe2
 }{
 	a: &fe2{
 		fe{0, 0, 0, 0, 0, 0},
@@ -148,12 +413,21 @@ var swuParamsForG2 = struct {
 		fe{9794203289623549276, 7309342082925068282, 1139538881605221074, 15659550692327388916, 16008355200866287827, 582484205531694093},
 		fe{4897101644811774638, 3654671041462534141, 569769440802610537, 17053147383018470266, 17227549637287919721, 291242102765847046},
 	},
-	zInv: &fe2{
-		fe{949978046398372251, 9282594348372276018, 12783089135259591524, 8269126545290330608, 594742981125487701, 491256564635846792},
-		fe{15449598521694521480, 14910517655282017894, 6549664756007020895, 17931849781271742567, 2408680398672607296, 691271026505846537},
+	minusZInv: &fe2{
+		fe{12452452969679491344, 11374291236854484173, 13099329512014041791, 17416955488833933518, 4817360797345214593, 1382542053011693074},
+		fe{16399576568092893731, 5746367929944742296, 886009817557060804, 7754232252852521560, 3003423379798094998, 1182527591141693329},
 	},
 	minusBOverA: &fe2{
 		fe{10393275865055580083, 6888480573845999877, 11497223857339693790, 14306043441748627554, 5078453791572287059, 1040691004897901061},
 		fe{3009155151022283512, 13768405011380760314, 14385194789933939525, 11380038592375636572, 333649986898415235, 833107612749638805},
+	}, sqrtRatioC3: new(big.Int).SetBytes(
+		fromHex(-1, "0x2a437a4b8c35fc74bd278eaa22f25e9e2dc90e50e7046b466e59e49349e8bd050a62cfd16ddca6ef53149330978ef011d68619c86185c7b292e85a87091a04966bf91ed3e71b743162c338362113cfd7ced6b1d76382eab26aa00001c718e3")),
+	zPowC2: &fe2{
+		fe{8921533702591418330, 15859389534032789116, 3389114680249073393, 15116930867080254631, 3288288975085550621, 1021049300055853010},
+		fe{8921533702591418330, 15859389534032789116, 3389114680249073393, 15116930867080254631, 3288288975085550621, 1021049300055853010},
+	},
+	zPowC2Plus1Over2: &fe2{
+		fe{1921729236329761493, 9193968980645934504, 9862280504246317678, 6861748847800817560, 10375788487011937166, 4460107375738415},
+		fe{16821121318233475459, 10183025025229892778, 1779012082459463630, 3442292649700377418, 1061500799026501234, 1352426537312017168},
 	},
 }